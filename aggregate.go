@@ -0,0 +1,66 @@
+package entitydebs
+
+import (
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+// Aggregate incrementally accumulates heads, dependents and sentiment of
+// [Frames] without retaining them. It is meant to be used with streams, see
+// [NewStream].
+type Aggregate struct {
+	// Heads counts head tokens of entity tokens by their text.
+	Heads map[string]int
+	// Dependents counts dependent tokens of entity tokens by their text.
+	Dependents map[string]int
+	// Frames is the number of aggregated data frames.
+	Frames int
+
+	// score and magnitude are the summed up document sentiments.
+	score, magnitude float64
+	// sentiments is the number of frames with a document sentiment.
+	sentiments int
+}
+
+// NewAggregate returns a new, empty aggregate.
+func NewAggregate() *Aggregate {
+	return &Aggregate{
+		Heads:      make(map[string]int),
+		Dependents: make(map[string]int),
+	}
+}
+
+// Add adds the heads, dependents and sentiment of frames to the aggregate.
+func (a *Aggregate) Add(frames Frames) {
+	forest := frames.Forest()
+	for _, head := range forest.Heads(nil) {
+		a.Heads[head.Text.Content]++
+	}
+	for _, dependent := range forest.Dependents(nil) {
+		a.Dependents[dependent.Text.Content]++
+	}
+
+	for _, frame := range frames.frames {
+		a.Frames++
+
+		if frame.sentiment == nil {
+			continue
+		}
+		a.score += float64(frame.sentiment.Score)
+		a.magnitude += float64(frame.sentiment.Magnitude)
+		a.sentiments++
+	}
+}
+
+// Sentiment returns the mean document sentiment of all aggregated frames with
+// a sentiment. If there are none, Sentiment returns nil.
+func (a *Aggregate) Sentiment() *tokenize.Sentiment {
+	if a.sentiments == 0 {
+		return nil
+	}
+
+	n := float64(a.sentiments)
+	return &tokenize.Sentiment{
+		Score:     float32(a.score / n),
+		Magnitude: float32(a.magnitude / n),
+	}
+}
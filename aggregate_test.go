@@ -0,0 +1,52 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/testhelper"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+
+	aggregate := NewAggregate()
+	for _, score := range []float32{0.5, -0.1} {
+		var (
+			sentence = testhelper.NewExampleSentence1(t, 0)
+			tokens   = testhelper.NewExampleTokens1(t, 0, 0)
+		)
+		aggregate.Add(Frames{
+			frames: []frame{
+				{
+					sentences: []*tokenize.Sentence{sentence},
+					tokens:    tokens,
+					sentiment: &tokenize.Sentiment{Score: score, Magnitude: 1},
+					entities: map[int][]*tokenize.Token{
+						4: {tokens[4]}, // flight
+					},
+				},
+			},
+		})
+	}
+
+	if aggregate.Frames != 2 {
+		t.Errorf("Aggregate.Frames = %d, want 2", aggregate.Frames)
+	}
+	if diff := cmp.Diff(map[string]int{"prefer": 2}, aggregate.Heads); diff != "" {
+		t.Errorf("Aggregate.Heads mismatch (-want +got):\n%s", diff)
+	}
+	want := map[string]int{"the": 2, "morning": 2, "through": 2}
+	if diff := cmp.Diff(want, aggregate.Dependents); diff != "" {
+		t.Errorf("Aggregate.Dependents mismatch (-want +got):\n%s", diff)
+	}
+	sentiment := aggregate.Sentiment()
+	if sentiment.Score != 0.2 || sentiment.Magnitude != 1 {
+		t.Errorf("Aggregate.Sentiment() = %v, want {1 0.2}", sentiment)
+	}
+
+	if got := NewAggregate().Sentiment(); got != nil {
+		t.Errorf("NewAggregate().Sentiment() = %v, want nil", got)
+	}
+}
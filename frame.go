@@ -9,6 +9,13 @@ import (
 // frame represents a text, consisting of sentences, tokens, sentiment and
 // entities.
 type frame struct {
+	// index is the index of the text of the frame.
+	index int
+	// metadata contains the metadata of the text, see [WithMetadata].
+	metadata map[string]string
+	// language is the language of the text, if known.
+	language string
+
 	sentences []*tokenize.Sentence
 	tokens    []*tokenize.Token
 	sentiment *tokenize.Sentiment
@@ -17,6 +24,21 @@ type frame struct {
 	// The final offset can be obtained by the adding the offset to the length
 	// of the tokens. The offset is zero-based.
 	entities map[int][]*tokenize.Token
+	// matches is a map of the entities starting offset and the matched entity.
+	matches map[int]match
+	// rejections contains all matches rejected by a rule.
+	rejections []Rejection
+}
+
+// match is an entity match within a frame.
+type match struct {
+	// id is the entity ID.
+	id string
+	// alias is the matched alias.
+	alias string
+	// coref is true if the match is a coreference to a preceding match, see
+	// [Frames.Resolve].
+	coref bool
 }
 
 // all yields all tokens of all sentences of a frame and the token offset to the
@@ -57,3 +79,47 @@ func (f frame) all() iter.Seq2[int32, []*tokenize.Token] {
 		}
 	}
 }
+
+// sentenceIndices returns the sentence index of every token.
+func (f frame) sentenceIndices() []int {
+	var (
+		indices = make([]int, 0, len(f.tokens))
+		i       int
+	)
+	for _, tokens := range f.all() {
+		for range tokens {
+			indices = append(indices, i)
+		}
+		i++
+	}
+	// Tokens that don't belong to a sentence are assigned to the last one.
+	for len(indices) < len(f.tokens) {
+		indices = append(indices, max(i-1, 0))
+	}
+
+	return indices
+}
+
+// sentenceBounds returns the token index of the start and the exclusive
+// token index of the end of the sentence of every token.
+func (f frame) sentenceBounds() (starts, ends []int) {
+	indices := f.sentenceIndices()
+	starts = make([]int, len(indices))
+	ends = make([]int, len(indices))
+	for i := range indices {
+		if i > 0 && indices[i] == indices[i-1] {
+			starts[i] = starts[i-1]
+		} else {
+			starts[i] = i
+		}
+	}
+	for i := len(indices) - 1; i >= 0; i-- {
+		if i == len(indices)-1 || indices[i] != indices[i+1] {
+			ends[i] = i + 1
+		} else {
+			ends[i] = ends[i+1]
+		}
+	}
+
+	return starts, ends
+}
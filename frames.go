@@ -14,6 +14,8 @@ type Frames struct {
 
 	// entities contains the tokenized entity.
 	entities map[string][]tokenize.Token
+	// ids contains the entity ID of every alias.
+	ids map[string]string
 
 	// deps contains dependency trees of all frames.
 	deps deps
@@ -0,0 +1,634 @@
+package entitydebs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+const (
+	// binaryMagic identifies the binary encoding of [Frames].
+	binaryMagic = "EDBF"
+	// binaryVersion is the version of the binary encoding of [Frames].
+	binaryVersion = 1
+
+	// binaryPrealloc limits preallocations of decoded lengths, so corrupt
+	// inputs can't exhaust memory.
+	binaryPrealloc = 1 << 10
+)
+
+// Frame markers precede every frame and end the encoding, so frames can be
+// written one at a time.
+const (
+	markerEnd = iota
+	markerFrame
+)
+
+// Token and sentence presence flags.
+const (
+	flagText = 1 << iota
+	flagPartOfSpeech
+	flagDependencyEdge
+	flagSentiment
+)
+
+type (
+	// byteReader reads single bytes without buffering beyond them.
+	byteReader interface {
+		io.Reader
+		io.ByteReader
+	}
+
+	// encoder writes the binary encoding. The first error is kept and all
+	// subsequent writes are skipped.
+	encoder struct {
+		w   *bufio.Writer
+		n   int64
+		err error
+		buf [binary.MaxVarintLen64]byte
+	}
+
+	// framesWriter writes the binary encoding one frame at a time.
+	framesWriter struct {
+		enc *encoder
+		// ids contains the entity IDs of the header, if written.
+		ids    map[string]string
+		header bool
+		closed bool
+	}
+
+	// decoder reads the binary encoding. The first error is kept and all
+	// subsequent reads return zero values.
+	decoder struct {
+		r   byteReader
+		n   int64
+		err error
+	}
+)
+
+// WriteTo writes the compact binary encoding of f to w. It includes entity
+// mentions and metadata. See [NewFramesWriter] to write frames one at a time.
+func (f Frames) WriteTo(w io.Writer) (int64, error) {
+	fw := NewFramesWriter(w)
+	if err := fw.Write(f); err != nil {
+		return fw.enc.n, err
+	}
+	err := fw.Close()
+
+	return fw.enc.n, err
+}
+
+// NewFramesWriter returns a writer of the binary encoding of [Frames] to w that
+// writes frames one at a time, e.g., the [Frames] of a stream, so they don't
+// need to be collected first. Close ends the encoding. The encoding is read by
+// [Frames.ReadFrom] and [ReadFrames].
+func NewFramesWriter(w io.Writer) *framesWriter {
+	return &framesWriter{
+		enc: &encoder{w: bufio.NewWriter(w)},
+	}
+}
+
+// Write writes the frames of f. Entities are written once with the first
+// [Frames], later [Frames] must have the same entity IDs.
+func (fw *framesWriter) Write(f Frames) error {
+	switch {
+	case fw.closed:
+		return errors.New("entitydebs: frames writer closed")
+	case !fw.header:
+		fw.writeHeader(f.entities, f.ids)
+	case !maps.Equal(fw.ids, f.ids):
+		return errors.New("entitydebs: frames of different entities")
+	}
+
+	enc := fw.enc
+	for _, frame := range f.frames {
+		enc.uvarint(markerFrame)
+		enc.frame(frame.marshal())
+	}
+
+	return enc.err
+}
+
+// Close ends the encoding and flushes it. It doesn't close the underlying
+// writer.
+func (fw *framesWriter) Close() error {
+	if fw.closed {
+		return fw.enc.err
+	}
+	if !fw.header {
+		fw.writeHeader(nil, nil)
+	}
+	fw.closed = true
+
+	enc := fw.enc
+	enc.uvarint(markerEnd)
+	if enc.err == nil {
+		enc.err = enc.w.Flush()
+	}
+
+	return enc.err
+}
+
+// writeHeader writes the header, which contains the entities.
+func (fw *framesWriter) writeHeader(entities map[string][]tokenize.Token, ids map[string]string) {
+	fw.header = true
+	fw.ids = ids
+
+	enc := fw.enc
+	enc.bytes([]byte(binaryMagic))
+	enc.uvarint(binaryVersion)
+
+	enc.uvarint(uint64(len(entities)))
+	for _, alias := range slices.Sorted(maps.Keys(entities)) {
+		enc.string(alias)
+		tokens := entities[alias]
+		enc.uvarint(uint64(len(tokens)))
+		for _, token := range tokens {
+			enc.token(&token)
+		}
+	}
+	enc.uvarint(uint64(len(ids)))
+	for _, alias := range slices.Sorted(maps.Keys(ids)) {
+		enc.string(alias)
+		enc.string(ids[alias])
+	}
+}
+
+// ReadFrom reads the binary encoding written by [Frames.WriteTo] from r and
+// replaces f. The forest is computed anew. If r doesn't implement
+// [io.ByteReader], ReadFrom may read beyond the encoding. See [ReadFrames] to
+// decode one frame at a time.
+func (f *Frames) ReadFrom(r io.Reader) (int64, error) {
+	fd, err := newFramesDecoder(r)
+	if err != nil {
+		return fd.dec.n, err
+	}
+
+	frames := make([]frame, 0)
+	for {
+		frame, ok, err := fd.next()
+		if err != nil {
+			return fd.dec.n, err
+		}
+		if !ok {
+			break
+		}
+		frames = append(frames, frame)
+	}
+
+	*f = Frames{
+		frames:   frames,
+		entities: fd.entities,
+		ids:      fd.ids,
+	}
+
+	return fd.dec.n, nil
+}
+
+// ReadFrames returns an iterator over the frames of the binary encoding
+// written by [Frames.WriteTo], so large collections are decoded one frame at a
+// time. Every [Frames] contains a single data frame and shares the entities of
+// the encoding. Iteration stops after the first error. If r doesn't implement
+// [io.ByteReader], ReadFrames may read beyond the encoding.
+func ReadFrames(r io.Reader) iter.Seq2[Frames, error] {
+	return func(yield func(Frames, error) bool) {
+		fd, err := newFramesDecoder(r)
+		if err != nil {
+			yield(Frames{}, err)
+			return
+		}
+
+		for {
+			f, ok, err := fd.next()
+			if err != nil {
+				yield(Frames{}, err)
+				return
+			}
+			if !ok {
+				return
+			}
+
+			frames := Frames{
+				frames:   []frame{f},
+				entities: fd.entities,
+				ids:      fd.ids,
+			}
+			if !yield(frames, nil) {
+				return
+			}
+		}
+	}
+}
+
+// framesDecoder decodes the frames of the binary encoding one at a time.
+type framesDecoder struct {
+	dec      *decoder
+	entities map[string][]tokenize.Token
+	ids      map[string]string
+	// i is the number of decoded frames.
+	i    int
+	done bool
+}
+
+// newFramesDecoder reads the header of the binary encoding from r, which
+// contains the entities.
+func newFramesDecoder(r io.Reader) (*framesDecoder, error) {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	var (
+		dec = &decoder{r: br}
+		fd  = &framesDecoder{dec: dec}
+	)
+
+	if magic := dec.bytes(len(binaryMagic)); dec.err == nil && string(magic) != binaryMagic {
+		return fd, errors.New("entitydebs: not a frames encoding")
+	}
+	if version := dec.uvarint(); dec.err == nil && version != binaryVersion {
+		return fd, fmt.Errorf("entitydebs: unsupported frames version %d", version)
+	}
+
+	// Entities
+	n := dec.uvarint()
+	fd.entities = make(map[string][]tokenize.Token, min(n, binaryPrealloc))
+	for range n {
+		if dec.err != nil {
+			break
+		}
+		alias := dec.string()
+		m := dec.uvarint()
+		tokens := make([]tokenize.Token, 0, min(m, binaryPrealloc))
+		for range m {
+			if dec.err != nil {
+				break
+			}
+			tokens = append(tokens, *dec.token())
+		}
+		fd.entities[alias] = tokens
+	}
+	n = dec.uvarint()
+	fd.ids = make(map[string]string, min(n, binaryPrealloc))
+	for range n {
+		if dec.err != nil {
+			break
+		}
+		alias := dec.string()
+		fd.ids[alias] = dec.string()
+	}
+
+	return fd, dec.err
+}
+
+// next decodes the next frame. It returns false after the last frame.
+func (fd *framesDecoder) next() (frame, bool, error) {
+	if fd.done {
+		return frame{}, false, nil
+	}
+	switch marker := fd.dec.uvarint(); {
+	case fd.dec.err != nil:
+		return frame{}, false, fd.dec.err
+	case marker == markerEnd:
+		fd.done = true
+		return frame{}, false, nil
+	case marker != markerFrame:
+		return frame{}, false, fmt.Errorf("entitydebs: frame %d: invalid marker %d", fd.i, marker)
+	}
+
+	fj := fd.dec.frame()
+	if fd.dec.err != nil {
+		return frame{}, false, fd.dec.err
+	}
+	f, err := fj.unmarshal()
+	if err != nil {
+		return frame{}, false, fmt.Errorf("entitydebs: frame %d: %w", fd.i, err)
+	}
+	fd.i++
+
+	return f, true, nil
+}
+
+func (enc *encoder) frame(fj frameJSON) {
+	enc.uvarint(uint64(fj.Index))
+	enc.uvarint(uint64(len(fj.Metadata)))
+	for _, key := range slices.Sorted(maps.Keys(fj.Metadata)) {
+		enc.string(key)
+		enc.string(fj.Metadata[key])
+	}
+	enc.string(fj.Language)
+
+	enc.uvarint(uint64(len(fj.Sentences)))
+	for _, sentence := range fj.Sentences {
+		var flags uint64
+		if sentence.Text != nil {
+			flags |= flagText
+		}
+		if sentence.Sentiment != nil {
+			flags |= flagSentiment
+		}
+		enc.uvarint(flags)
+		if sentence.Text != nil {
+			enc.span(sentence.Text)
+		}
+		if sentence.Sentiment != nil {
+			enc.sentiment(sentence.Sentiment)
+		}
+	}
+	enc.uvarint(uint64(len(fj.Tokens)))
+	for _, token := range fj.Tokens {
+		enc.token(token)
+	}
+	if fj.Sentiment != nil {
+		enc.uvarint(flagSentiment)
+		enc.sentiment(fj.Sentiment)
+	} else {
+		enc.uvarint(0)
+	}
+
+	for _, mentions := range [][]mentionJSON{fj.Mentions, fj.Rejections} {
+		enc.uvarint(uint64(len(mentions)))
+		for _, mention := range mentions {
+			enc.uvarint(uint64(mention.Offset))
+			enc.uvarint(uint64(mention.Length))
+			enc.string(mention.Entity)
+			enc.string(mention.Alias)
+			if mention.Coref {
+				enc.uvarint(1)
+			} else {
+				enc.uvarint(0)
+			}
+			enc.varint(int64(mention.Reason))
+		}
+	}
+}
+
+func (dec *decoder) frame() frameJSON {
+	fj := frameJSON{
+		Index: int(dec.uvarint()),
+	}
+	if n := dec.uvarint(); n > 0 {
+		fj.Metadata = make(map[string]string, min(n, binaryPrealloc))
+		for range n {
+			if dec.err != nil {
+				break
+			}
+			key := dec.string()
+			fj.Metadata[key] = dec.string()
+		}
+	}
+	fj.Language = dec.string()
+
+	n := dec.uvarint()
+	fj.Sentences = make([]*tokenize.Sentence, 0, min(n, binaryPrealloc))
+	for range n {
+		if dec.err != nil {
+			break
+		}
+		sentence := &tokenize.Sentence{}
+		flags := dec.uvarint()
+		if flags&flagText != 0 {
+			sentence.Text = dec.span()
+		}
+		if flags&flagSentiment != 0 {
+			sentence.Sentiment = dec.sentiment()
+		}
+		fj.Sentences = append(fj.Sentences, sentence)
+	}
+	n = dec.uvarint()
+	fj.Tokens = make([]*tokenize.Token, 0, min(n, binaryPrealloc))
+	for range n {
+		if dec.err != nil {
+			break
+		}
+		fj.Tokens = append(fj.Tokens, dec.token())
+	}
+	if dec.uvarint()&flagSentiment != 0 {
+		fj.Sentiment = dec.sentiment()
+	}
+
+	for _, mentions := range []*[]mentionJSON{&fj.Mentions, &fj.Rejections} {
+		n := dec.uvarint()
+		*mentions = make([]mentionJSON, 0, min(n, binaryPrealloc))
+		for range n {
+			if dec.err != nil {
+				break
+			}
+			*mentions = append(*mentions, mentionJSON{
+				Offset: int(dec.uvarint()),
+				Length: int(dec.uvarint()),
+				Entity: dec.string(),
+				Alias:  dec.string(),
+				Coref:  dec.uvarint() != 0,
+				Reason: Reason(dec.varint()),
+			})
+		}
+	}
+
+	return fj
+}
+
+func (enc *encoder) token(token *tokenize.Token) {
+	var flags uint64
+	if token.Text != nil {
+		flags |= flagText
+	}
+	if token.PartOfSpeech != nil {
+		flags |= flagPartOfSpeech
+	}
+	if token.DependencyEdge != nil {
+		flags |= flagDependencyEdge
+	}
+	enc.uvarint(flags)
+
+	if token.Text != nil {
+		enc.span(token.Text)
+	}
+	if pos := token.PartOfSpeech; pos != nil {
+		for _, v := range []int32{
+			int32(pos.Tag),
+			int32(pos.Aspect),
+			int32(pos.Case),
+			int32(pos.Form),
+			int32(pos.Gender),
+			int32(pos.Mood),
+			int32(pos.Number),
+			int32(pos.Person),
+			int32(pos.Proper),
+			int32(pos.Reciprocity),
+			int32(pos.Tense),
+			int32(pos.Voice),
+		} {
+			enc.varint(int64(v))
+		}
+	}
+	if edge := token.DependencyEdge; edge != nil {
+		enc.varint(int64(edge.HeadTokenIndex))
+		enc.varint(int64(edge.Label))
+	}
+	enc.string(token.Lemma)
+}
+
+func (dec *decoder) token() *tokenize.Token {
+	token := &tokenize.Token{}
+	flags := dec.uvarint()
+
+	if flags&flagText != 0 {
+		token.Text = dec.span()
+	}
+	if flags&flagPartOfSpeech != 0 {
+		token.PartOfSpeech = &tokenize.PartOfSpeech{
+			Tag:         tokenize.PartOfSpeechTag(dec.varint()),
+			Aspect:      tokenize.PartOfSpeechAspect(dec.varint()),
+			Case:        tokenize.PartOfSpeechCase(dec.varint()),
+			Form:        tokenize.PartOfSpeechForm(dec.varint()),
+			Gender:      tokenize.PartOfSpeechGender(dec.varint()),
+			Mood:        tokenize.PartOfSpeechMood(dec.varint()),
+			Number:      tokenize.PartOfSpeechNumber(dec.varint()),
+			Person:      tokenize.PartOfSpeechPerson(dec.varint()),
+			Proper:      tokenize.PartOfSpeechProper(dec.varint()),
+			Reciprocity: tokenize.PartOfSpeechReciprocity(dec.varint()),
+			Tense:       tokenize.PartOfSpeechTense(dec.varint()),
+			Voice:       tokenize.PartOfSpeechVoice(dec.varint()),
+		}
+	}
+	if flags&flagDependencyEdge != 0 {
+		token.DependencyEdge = &tokenize.DependencyEdge{
+			HeadTokenIndex: int32(dec.varint()),
+			Label:          tokenize.DependencyEdgeLabel(dec.varint()),
+		}
+	}
+	token.Lemma = dec.string()
+
+	return token
+}
+
+func (enc *encoder) span(span *tokenize.TextSpan) {
+	enc.string(span.Content)
+	enc.varint(int64(span.BeginOffset))
+}
+
+func (dec *decoder) span() *tokenize.TextSpan {
+	return &tokenize.TextSpan{
+		Content:     dec.string(),
+		BeginOffset: int32(dec.varint()),
+	}
+}
+
+func (enc *encoder) sentiment(sentiment *tokenize.Sentiment) {
+	enc.uvarint(uint64(math.Float32bits(sentiment.Magnitude)))
+	enc.uvarint(uint64(math.Float32bits(sentiment.Score)))
+}
+
+func (dec *decoder) sentiment() *tokenize.Sentiment {
+	return &tokenize.Sentiment{
+		Magnitude: math.Float32frombits(uint32(dec.uvarint())),
+		Score:     math.Float32frombits(uint32(dec.uvarint())),
+	}
+}
+
+func (enc *encoder) uvarint(v uint64) {
+	enc.bytes(enc.buf[:binary.PutUvarint(enc.buf[:], v)])
+}
+
+func (enc *encoder) varint(v int64) {
+	enc.bytes(enc.buf[:binary.PutVarint(enc.buf[:], v)])
+}
+
+func (enc *encoder) string(s string) {
+	enc.uvarint(uint64(len(s)))
+	if enc.err != nil {
+		return
+	}
+	n, err := enc.w.WriteString(s)
+	enc.n += int64(n)
+	enc.err = err
+}
+
+func (enc *encoder) bytes(b []byte) {
+	if enc.err != nil {
+		return
+	}
+	n, err := enc.w.Write(b)
+	enc.n += int64(n)
+	enc.err = err
+}
+
+func (dec *decoder) ReadByte() (byte, error) {
+	b, err := dec.r.ReadByte()
+	if err == nil {
+		dec.n++
+	}
+
+	return b, err
+}
+
+func (dec *decoder) uvarint() uint64 {
+	if dec.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(dec)
+	dec.fail(err)
+
+	return v
+}
+
+func (dec *decoder) varint() int64 {
+	if dec.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(dec)
+	dec.fail(err)
+
+	return v
+}
+
+func (dec *decoder) string() string {
+	n := dec.uvarint()
+	if dec.err != nil || n == 0 {
+		return ""
+	}
+	if n > math.MaxInt32 {
+		dec.fail(fmt.Errorf("entitydebs: string too long: %d", n))
+		return ""
+	}
+
+	return string(dec.bytes(int(n)))
+}
+
+func (dec *decoder) bytes(n int) []byte {
+	if dec.err != nil {
+		return nil
+	}
+	b := make([]byte, 0, min(n, binaryPrealloc))
+	for len(b) < n {
+		chunk := make([]byte, min(n-len(b), binaryPrealloc))
+		m, err := io.ReadFull(dec.r, chunk)
+		dec.n += int64(m)
+		b = append(b, chunk[:m]...)
+		if err != nil {
+			dec.fail(err)
+			return nil
+		}
+	}
+
+	return b
+}
+
+// fail keeps the first error. Premature ends of input are unexpected.
+func (dec *decoder) fail(err error) {
+	if err == nil || dec.err != nil {
+		return
+	}
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	dec.err = err
+}
@@ -0,0 +1,180 @@
+package entitydebs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestFramesReadFrom(t *testing.T) {
+	t.Parallel()
+
+	var (
+		frames = newExampleFrames(t)
+		buf    bytes.Buffer
+	)
+	n, err := frames.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("Frames.WriteTo() = _, %s, want nil", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("Frames.WriteTo() = %d, _, want %d", n, buf.Len())
+	}
+	encoded := bytes.Clone(buf.Bytes())
+
+	var got Frames
+	m, err := got.ReadFrom(&buf)
+	if err != nil {
+		t.Fatalf("Frames.ReadFrom() = _, %s, want nil", err)
+	}
+	if m != n {
+		t.Errorf("Frames.ReadFrom() = %d, _, want %d", m, n)
+	}
+
+	// Decoded frames encode identically.
+	want, _ := json.Marshal(frames)
+	if b, _ := json.Marshal(got); !bytes.Equal(b, want) {
+		t.Errorf("Frames.ReadFrom() = %s, want %s", b, want)
+	}
+	if len(encoded) >= len(want) {
+		t.Errorf("len(Frames.WriteTo()) = %d, want less than JSON %d", len(encoded), len(want))
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+
+		var frames Frames
+		if _, err := frames.ReadFrom(bytes.NewReader(encoded[:len(encoded)/2])); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Frames.ReadFrom() = _, %v, want %s", err, io.ErrUnexpectedEOF)
+		}
+	})
+	t.Run("not a frames encoding", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			frames Frames
+			want   = "entitydebs: not a frames encoding"
+		)
+		if _, err := frames.ReadFrom(bytes.NewReader([]byte("JSON{}"))); err == nil || err.Error() != want {
+			t.Errorf("Frames.ReadFrom() = _, %v, want %s", err, want)
+		}
+	})
+}
+
+func TestReadFrames(t *testing.T) {
+	t.Parallel()
+
+	var (
+		frames = newExampleFrames(t)
+		buf    bytes.Buffer
+	)
+	if _, err := frames.WriteTo(&buf); err != nil {
+		t.Fatalf("Frames.WriteTo() = _, %s, want nil", err)
+	}
+	encoded := bytes.Clone(buf.Bytes())
+
+	i := 0
+	for got, err := range ReadFrames(&buf) {
+		if err != nil {
+			t.Fatalf("ReadFrames() = _, %s, want nil", err)
+		}
+		want, _ := json.Marshal(Frames{
+			frames:   frames.frames[i : i+1],
+			entities: frames.entities,
+			ids:      frames.ids,
+		})
+		if b, _ := json.Marshal(got); !bytes.Equal(b, want) {
+			t.Errorf("ReadFrames() frame %d = %s, want %s", i, b, want)
+		}
+		i++
+	}
+	if i != len(frames.frames) {
+		t.Errorf("len(ReadFrames()) = %d, want %d", i, len(frames.frames))
+	}
+
+	t.Run("trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		// Bytes following the encoding are not consumed.
+		r := bytes.NewReader(append(bytes.Clone(encoded), "tail"...))
+		var frames Frames
+		if _, err := frames.ReadFrom(r); err != nil {
+			t.Fatalf("Frames.ReadFrom() = _, %s, want nil", err)
+		}
+		if tail, _ := io.ReadAll(r); string(tail) != "tail" {
+			t.Errorf("io.ReadAll() = %q, want %q", tail, "tail")
+		}
+	})
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+
+		var errs int
+		for _, err := range ReadFrames(bytes.NewReader(encoded[:len(encoded)-1])) {
+			if err != nil {
+				errs++
+				if !errors.Is(err, io.ErrUnexpectedEOF) {
+					t.Errorf("ReadFrames() = _, %v, want %s", err, io.ErrUnexpectedEOF)
+				}
+			}
+		}
+		if errs != 1 {
+			t.Errorf("ReadFrames() errors = %d, want 1", errs)
+		}
+	})
+}
+
+func TestNewFramesWriter(t *testing.T) {
+	t.Parallel()
+
+	frames := newExampleFrames(t)
+	var want bytes.Buffer
+	if _, err := frames.WriteTo(&want); err != nil {
+		t.Fatalf("Frames.WriteTo() = _, %s, want nil", err)
+	}
+
+	// Frames written one at a time encode like all frames at once.
+	var (
+		buf bytes.Buffer
+		fw  = NewFramesWriter(&buf)
+	)
+	for i := range frames.frames {
+		if err := fw.Write(frames.Slice(i, i+1)); err != nil {
+			t.Fatalf("framesWriter.Write() = %s, want nil", err)
+		}
+	}
+	if err := fw.Close(); err != nil {
+		t.Fatalf("framesWriter.Close() = %s, want nil", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want.Bytes()) {
+		t.Errorf("framesWriter = %x, want %x", buf.Bytes(), want.Bytes())
+	}
+	if err := fw.Write(frames); err == nil {
+		t.Error("framesWriter.Write() after Close() = nil, want error")
+	}
+
+	t.Run("different entities", func(t *testing.T) {
+		t.Parallel()
+
+		fw := NewFramesWriter(io.Discard)
+		if err := fw.Write(frames); err != nil {
+			t.Fatalf("framesWriter.Write() = %s, want nil", err)
+		}
+		if err := fw.Write(Frames{ids: map[string]string{"UK": "uk"}}); err == nil {
+			t.Error("framesWriter.Write() = nil, want error")
+		}
+	})
+	t.Run("no frames", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		if err := NewFramesWriter(&buf).Close(); err != nil {
+			t.Fatalf("framesWriter.Close() = %s, want nil", err)
+		}
+		var frames Frames
+		if _, err := frames.ReadFrom(&buf); err != nil || len(frames.frames) != 0 {
+			t.Errorf("Frames.ReadFrom() = %d frames, %v, want 0 frames, nil", len(frames.frames), err)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package entitydebs
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+type (
+	// Collocations configures a collocation analysis between entity mentions
+	// and co-occurring tokens, see [Frames.Collocations].
+	Collocations struct {
+		// Entity restricts mentions to the entity with the ID. Empty
+		// considers all entities.
+		Entity string
+		// By is the compared token property, usually [PropertyLemma].
+		By Property
+		// Cooccurrence defines which tokens co-occur with a mention.
+		Cooccurrence Cooccurrence
+		// Window is the number of tokens before and after a mention within
+		// its sentence, for [CooccurrenceWindow]. Zero defaults to 5.
+		Window int
+		// Stopwords contains excluded token texts, compared
+		// case-insensitively.
+		Stopwords []string
+		// MinCooccurrence is the minimum number of co-occurrences of a
+		// collocate.
+		MinCooccurrence int
+		// MinFrequency is the minimum frequency of a collocate within all
+		// frames.
+		MinFrequency int
+		// Rank is the measure collocations are ordered by.
+		Rank Measure
+	}
+
+	// Cooccurrence defines co-occurring tokens.
+	Cooccurrence int
+
+	// Measure is an association measure.
+	Measure int
+
+	// Collocation is the association between entity mentions and a key.
+	Collocation struct {
+		Key string
+		// Observed is the number of co-occurrences.
+		Observed int
+		// Expected is the number of co-occurrences expected by chance.
+		Expected float64
+		// Frequency is the frequency of the key within all frames.
+		Frequency int
+		// PMI is the pointwise mutual information, in bits.
+		PMI float64
+		// LogLikelihood is the log-likelihood ratio G².
+		LogLikelihood float64
+		// TScore is the t-score.
+		TScore float64
+		// Dice is the Dice coefficient.
+		Dice float64
+	}
+)
+
+const (
+	// CooccurrenceWindow considers tokens within a window around mentions.
+	// This is the default.
+	CooccurrenceWindow Cooccurrence = iota
+	// CooccurrenceDependency considers heads and dependents of mentions, see
+	// [Frames.Forest].
+	CooccurrenceDependency
+)
+
+const (
+	// MeasureLogLikelihood ranks by log-likelihood. This is the default.
+	MeasureLogLikelihood Measure = iota
+	// MeasurePMI ranks by pointwise mutual information.
+	MeasurePMI
+	// MeasureTScore ranks by t-score.
+	MeasureTScore
+	// MeasureDice ranks by Dice coefficient.
+	MeasureDice
+)
+
+// Collocations returns the collocations of entity mentions, ordered by the
+// rank measure in descending order and then by key.
+//
+// Mention tokens are excluded from the counts. A token co-occurs at most once,
+// even if it's close to several mentions.
+func (f *Frames) Collocations(colls Collocations) []Collocation {
+	var (
+		nodes   = f.nodes(colls.Entity)
+		context map[*tokenize.Token]struct{}
+	)
+	switch colls.Cooccurrence {
+	case CooccurrenceDependency:
+		context = make(map[*tokenize.Token]struct{})
+		for token := range f.forest(colls.Entity).Relationships() {
+			context[token] = struct{}{}
+		}
+	default:
+		context = f.contexts(colls.Entity, colls.Window)
+	}
+
+	var (
+		stopwords   = newStoplist(colls.Stopwords)
+		frequencies = make(map[string]int)
+		observed    = make(map[string]int)
+		// n is the number of tokens, r the number of co-occurring tokens.
+		n, r int
+	)
+	for _, frame := range f.frames {
+		for _, token := range frame.tokens {
+			if _, ok := nodes[token]; ok {
+				continue
+			}
+			if stopwords.contains(token) {
+				continue
+			}
+			key, ok := colls.By.of(token)
+			if !ok {
+				continue
+			}
+
+			frequencies[key]++
+			n++
+			if _, ok := context[token]; ok {
+				observed[key]++
+				r++
+			}
+		}
+	}
+
+	collocations := make([]Collocation, 0, len(observed))
+	for _, key := range slices.Sorted(maps.Keys(observed)) {
+		var (
+			o = observed[key]
+			c = frequencies[key]
+		)
+		if o < colls.MinCooccurrence || c < colls.MinFrequency {
+			continue
+		}
+
+		e := float64(r) * float64(c) / float64(n)
+		collocations = append(collocations, Collocation{
+			Key:           key,
+			Observed:      o,
+			Expected:      e,
+			Frequency:     c,
+			PMI:           math.Log2(float64(o) / e),
+			LogLikelihood: logLikelihood(o, r-o, c-o, n-r-c+o),
+			TScore:        (float64(o) - e) / math.Sqrt(float64(o)),
+			Dice:          2 * float64(o) / float64(r+c),
+		})
+	}
+	slices.SortStableFunc(collocations, func(a, b Collocation) int {
+		return cmp.Compare(colls.Rank.of(b), colls.Rank.of(a))
+	})
+
+	return collocations
+}
+
+// of returns the measure of collocation.
+func (measure Measure) of(collocation Collocation) float64 {
+	switch measure {
+	case MeasurePMI:
+		return collocation.PMI
+	case MeasureTScore:
+		return collocation.TScore
+	case MeasureDice:
+		return collocation.Dice
+	default:
+		return collocation.LogLikelihood
+	}
+}
+
+// logLikelihood returns the log-likelihood ratio G² of the contingency table
+//
+//	o11 o12
+//	o21 o22
+func logLikelihood(o11, o12, o21, o22 int) float64 {
+	var (
+		n        = float64(o11 + o12 + o21 + o22)
+		rows     = [2]float64{float64(o11 + o12), float64(o21 + o22)}
+		cols     = [2]float64{float64(o11 + o21), float64(o12 + o22)}
+		observed = [2][2]int{{o11, o12}, {o21, o22}}
+		g2       float64
+	)
+	for i := range 2 {
+		for j := range 2 {
+			o := float64(observed[i][j])
+			if o == 0 {
+				continue
+			}
+			e := rows[i] * cols[j] / n
+			g2 += o * math.Log(o/e)
+		}
+	}
+
+	return 2 * g2
+}
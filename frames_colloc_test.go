@@ -0,0 +1,115 @@
+package entitydebs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ndabAP/entitydebs/testhelper"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestFramesCollocations(t *testing.T) {
+	t.Parallel()
+
+	var (
+		token = func(content string, offset int32, head int32, label tokenize.DependencyEdgeLabel) *tokenize.Token {
+			return testhelper.NewToken(t,
+				content,
+				offset,
+				testhelper.NewPoS(t, tokenize.PartOfSpeechTagUnknown, testhelper.PoSParams{}),
+				testhelper.NewDepEdge(t, head, label),
+				"",
+			)
+		}
+
+		// Germany is strong and Germany grows.
+		tokens1 = []*tokenize.Token{
+			token("Germany", 0, 1, tokenize.DependencyEdgeLabelNSubj),
+			token("is", 8, 1, tokenize.DependencyEdgeLabelRoot),
+			token("strong", 11, 1, tokenize.DependencyEdgeLabelAComp),
+			token("and", 18, 1, tokenize.DependencyEdgeLabelCC),
+			token("Germany", 22, 5, tokenize.DependencyEdgeLabelNSubj),
+			token("grows", 30, 1, tokenize.DependencyEdgeLabelConj),
+			token(".", 35, 1, tokenize.DependencyEdgeLabelP),
+		}
+		// Spain is big.
+		tokens2 = []*tokenize.Token{
+			token("Spain", 0, 1, tokenize.DependencyEdgeLabelNSubj),
+			token("is", 6, 1, tokenize.DependencyEdgeLabelRoot),
+			token("big", 9, 1, tokenize.DependencyEdgeLabelAComp),
+			token(".", 12, 1, tokenize.DependencyEdgeLabelP),
+		}
+	)
+
+	tests := []struct {
+		name  string
+		colls Collocations
+		want  []Collocation
+	}{
+		{
+			name:  "window",
+			colls: Collocations{Window: 1},
+			want: []Collocation{
+				{Key: "and", Observed: 1, Expected: 1.0 / 3, Frequency: 1, PMI: math.Log2(3), LogLikelihood: 2.4598927154056973, TScore: 2.0 / 3, Dice: 0.5},
+				{Key: "grows", Observed: 1, Expected: 1.0 / 3, Frequency: 1, PMI: math.Log2(3), LogLikelihood: 2.4598927154056973, TScore: 2.0 / 3, Dice: 0.5},
+				{Key: "is", Observed: 1, Expected: 2.0 / 3, Frequency: 2, PMI: math.Log2(1.5), LogLikelihood: 0.3088920668732478, TScore: 1.0 / 3, Dice: 0.4},
+			},
+		},
+		{
+			name:  "min frequency",
+			colls: Collocations{Window: 1, MinFrequency: 2, Rank: MeasurePMI},
+			want: []Collocation{
+				{Key: "is", Observed: 1, Expected: 2.0 / 3, Frequency: 2, PMI: math.Log2(1.5), LogLikelihood: 0.3088920668732478, TScore: 1.0 / 3, Dice: 0.4},
+			},
+		},
+		{
+			name:  "dependency",
+			colls: Collocations{Cooccurrence: CooccurrenceDependency, Entity: "de", Rank: MeasureDice},
+			want: []Collocation{
+				{Key: "grows", Observed: 1, Expected: 2.0 / 9, Frequency: 1, PMI: math.Log2(4.5), LogLikelihood: 3.5063890029347933, TScore: 7.0 / 9, Dice: 2.0 / 3},
+				{Key: "is", Observed: 1, Expected: 4.0 / 9, Frequency: 2, PMI: math.Log2(2.25), LogLikelihood: 1.0204944047602722, TScore: 5.0 / 9, Dice: 0.5},
+			},
+		},
+		{
+			name:  "unknown entity",
+			colls: Collocations{Entity: "fr"},
+			want:  []Collocation{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			frames := Frames{
+				frames: []frame{
+					{
+						sentences: []*tokenize.Sentence{
+							testhelper.NewSentence(t, "Germany is strong and Germany grows.", 0, nil),
+						},
+						tokens: tokens1,
+						entities: map[int][]*tokenize.Token{
+							0: {tokens1[0]},
+							4: {tokens1[4]},
+						},
+						matches: map[int]match{
+							0: {id: "de", alias: "Germany"},
+							4: {id: "de", alias: "Germany"},
+						},
+					},
+					{
+						sentences: []*tokenize.Sentence{
+							testhelper.NewSentence(t, "Spain is big.", 0, nil),
+						},
+						tokens: tokens2,
+					},
+				},
+			}
+			got := frames.Collocations(test.colls)
+			if diff := cmp.Diff(test.want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("Frames.Collocations() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
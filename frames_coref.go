@@ -0,0 +1,214 @@
+package entitydebs
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+	"golang.org/x/text/cases"
+)
+
+// Coreference configures the heuristic coreference resolution of
+// [Frames.Resolve].
+type Coreference struct {
+	// Pronouns contains the pronouns to link, e.g., "it" or "its". Pronouns
+	// are compared case-insensitively. If empty, every token tagged as pronoun
+	// is a candidate.
+	Pronouns []string
+	// Descriptions contains definite descriptions to link, e.g., "our country"
+	// or "this nation". Descriptions are tokenized by white space and compared
+	// case-insensitively.
+	Descriptions []string
+	// Roles restricts pronouns to the given dependency edge labels, e.g.,
+	// subjects. If empty, all roles are allowed.
+	Roles []tokenize.DependencyEdgeLabel
+	// Window is the maximum number of sentences between a reference and its
+	// entity mention. Zero restricts references to the sentence of the
+	// mention.
+	Window int
+}
+
+// Resolve links pronouns and definite descriptions to the nearest preceding
+// compatible entity mention within the same frame. A pronoun is compatible if
+// its gender, number and person don't contradict the last token of the
+// mention. Descriptions are always compatible.
+//
+// Linked tokens become entity tokens of the mention's entity, so they are
+// considered by [Frames.Forest]. Resolve modifies frames in place and
+// invalidates the cached forest.
+func (f *Frames) Resolve(coref Coreference) {
+	var (
+		fold = cases.Fold()
+
+		pronouns     = make([]string, 0, len(coref.Pronouns))
+		descriptions = make([][]string, 0, len(coref.Descriptions))
+	)
+	for _, pronoun := range coref.Pronouns {
+		pronouns = append(pronouns, fold.String(pronoun))
+	}
+	for _, description := range coref.Descriptions {
+		words := make([]string, 0)
+		for _, word := range strings.Fields(description) {
+			words = append(words, fold.String(word))
+		}
+		if len(words) > 0 {
+			descriptions = append(descriptions, words)
+		}
+	}
+
+	for index := range f.frames {
+		frame := &f.frames[index]
+		if frame.entities == nil {
+			frame.entities = make(map[int][]*tokenize.Token)
+		}
+		if frame.matches == nil {
+			frame.matches = make(map[int]match)
+		}
+
+		var (
+			sentences = frame.sentenceIndices()
+
+			// covered contains all token indices of entity mentions.
+			covered = make(map[int]struct{})
+			// antecedents contains the starting offsets of all mentions in
+			// ascending order.
+			antecedents = slices.Sorted(maps.Keys(frame.entities))
+		)
+		for offset, tokens := range frame.entities {
+			for k := range tokens {
+				covered[offset+k] = struct{}{}
+			}
+		}
+
+		i := 0
+		for i < len(frame.tokens) {
+			if _, ok := covered[i]; ok {
+				i++
+				continue
+			}
+
+			// Look for the longest description, then for a pronoun.
+			n, description := 0, false
+			for _, words := range descriptions {
+				if len(words) > n && matchWords(frame.tokens[i:], words, covered, i) {
+					n, description = len(words), true
+				}
+			}
+			if n == 0 && coref.pronoun(frame.tokens[i], pronouns) {
+				n = 1
+			}
+			if n == 0 {
+				i++
+				continue
+			}
+
+			// Find the nearest preceding compatible mention.
+			antecedent := -1
+			for _, offset := range slices.Backward(antecedents) {
+				if offset >= i {
+					continue
+				}
+				if sentences[i]-sentences[offset] > coref.Window {
+					break
+				}
+				tokens := frame.entities[offset]
+				if description || compatible(frame.tokens[i], tokens[len(tokens)-1]) {
+					antecedent = offset
+					break
+				}
+			}
+			if antecedent == -1 {
+				i += n
+				continue
+			}
+
+			// Link reference.
+			frame.entities[i] = slices.Clone(frame.tokens[i : i+n])
+			frame.matches[i] = match{
+				id:    frame.matches[antecedent].id,
+				alias: frame.matches[antecedent].alias,
+				coref: true,
+			}
+			for k := range n {
+				covered[i+k] = struct{}{}
+			}
+			// References can be antecedents themselves.
+			j, _ := slices.BinarySearch(antecedents, i)
+			antecedents = slices.Insert(antecedents, j, i)
+
+			i += n
+		}
+	}
+
+	// Invalidate forest.
+	f.deps = deps{}
+}
+
+// pronoun reports whether token is a pronoun to link.
+func (coref Coreference) pronoun(token *tokenize.Token, pronouns []string) bool {
+	if token.PartOfSpeech == nil || token.PartOfSpeech.Tag != tokenize.PartOfSpeechTagPron {
+		return false
+	}
+	if len(pronouns) > 0 && !slices.Contains(pronouns, cases.Fold().String(token.Text.Content)) {
+		return false
+	}
+	if len(coref.Roles) > 0 {
+		if token.DependencyEdge == nil || !slices.Contains(coref.Roles, token.DependencyEdge.Label) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compatible reports whether the gender, number and person of the pronoun
+// don't contradict the entity token. Unknown values are compatible. Entity
+// tokens without person are third person.
+func compatible(pronoun, entity *tokenize.Token) bool {
+	p, e := pronoun.PartOfSpeech, entity.PartOfSpeech
+	if p == nil || e == nil {
+		return true
+	}
+
+	if p.Gender != tokenize.PartOfSpeechGenderUnknown &&
+		e.Gender != tokenize.PartOfSpeechGenderUnknown &&
+		p.Gender != e.Gender {
+		return false
+	}
+	if p.Number != tokenize.PartOfSpeechNumberUnknown &&
+		e.Number != tokenize.PartOfSpeechNumberUnknown &&
+		p.Number != e.Number {
+		return false
+	}
+
+	person := e.Person
+	if person == tokenize.PartOfSpeechPersonUnknown {
+		person = tokenize.PartOfSpeechPersonThird
+	}
+	switch p.Person {
+	case tokenize.PartOfSpeechPersonUnknown, tokenize.PartOfSpeechPersonReflexive, person:
+		return true
+	default:
+		return false
+	}
+}
+
+// matchWords reports whether the tokens begin with words under case folding
+// and don't overlap covered tokens. offset is the index of the first token.
+func matchWords(tokens []*tokenize.Token, words []string, covered map[int]struct{}, offset int) bool {
+	if len(tokens) < len(words) {
+		return false
+	}
+	fold := cases.Fold()
+	for k, word := range words {
+		if _, ok := covered[offset+k]; ok {
+			return false
+		}
+		if fold.String(tokens[k].Text.Content) != word {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,156 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/testhelper"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestFramesResolve(t *testing.T) {
+	t.Parallel()
+
+	var (
+		token = func(content string, offset int32, params testhelper.PoSParams, head int32, label tokenize.DependencyEdgeLabel) *tokenize.Token {
+			return testhelper.NewToken(t,
+				content,
+				offset,
+				testhelper.NewPoS(t, params.Tag, params),
+				testhelper.NewDepEdge(t, head, label),
+				"",
+			)
+		}
+		singular = tokenize.PartOfSpeechNumberSingular
+		third    = tokenize.PartOfSpeechPersonThird
+
+		tokens = []*tokenize.Token{
+			// Germany is strong.
+			token("Germany", 0, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagNoun, Number: singular, Proper: tokenize.PartOfSpeechIsProper}, 1, tokenize.DependencyEdgeLabelNSubj),
+			token("is", 8, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagVerb}, 1, tokenize.DependencyEdgeLabelRoot),
+			token("strong", 11, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagAdj}, 1, tokenize.DependencyEdgeLabelAComp),
+			token(".", 17, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagPunct}, 1, tokenize.DependencyEdgeLabelP),
+			// It grows.
+			token("It", 19, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagPron, Number: singular, Person: third, Gender: tokenize.PartOfSpeechGenderNeuter}, 5, tokenize.DependencyEdgeLabelNSubj),
+			token("grows", 22, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagVerb}, 5, tokenize.DependencyEdgeLabelRoot),
+			token(".", 27, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagPunct}, 5, tokenize.DependencyEdgeLabelP),
+			// This nation prospers.
+			token("This", 29, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagDet}, 8, tokenize.DependencyEdgeLabelDet),
+			token("nation", 34, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagNoun, Number: singular}, 9, tokenize.DependencyEdgeLabelNSubj),
+			token("prospers", 41, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagVerb}, 9, tokenize.DependencyEdgeLabelRoot),
+			token(".", 49, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagPunct}, 9, tokenize.DependencyEdgeLabelP),
+			// They cheer.
+			token("They", 51, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagPron, Number: tokenize.PartOfSpeechNumberPlural, Person: third}, 12, tokenize.DependencyEdgeLabelNSubj),
+			token("cheer", 56, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagVerb}, 12, tokenize.DependencyEdgeLabelRoot),
+			token(".", 61, testhelper.PoSParams{Tag: tokenize.PartOfSpeechTagPunct}, 12, tokenize.DependencyEdgeLabelP),
+		}
+		frames = Frames{
+			frames: []frame{
+				{
+					sentences: []*tokenize.Sentence{
+						testhelper.NewSentence(t, "Germany is strong.", 0, nil),
+						testhelper.NewSentence(t, "It grows.", 19, nil),
+						testhelper.NewSentence(t, "This nation prospers.", 29, nil),
+						testhelper.NewSentence(t, "They cheer.", 51, nil),
+					},
+					tokens: tokens,
+					entities: map[int][]*tokenize.Token{
+						0: {tokens[0]},
+					},
+					matches: map[int]match{
+						0: {id: "de", alias: "Germany"},
+					},
+				},
+			},
+		}
+	)
+
+	// Derived frames are resolved independently.
+	derived := frames.Slice(0, 1)
+	derived.Resolve(Coreference{Pronouns: []string{"it"}, Window: 2})
+	if got := len(derived.frames[0].matches); got != 2 {
+		t.Errorf("len(Frames.Slice().Resolve() matches) = %d, want 2", got)
+	}
+	if got := len(frames.frames[0].matches); got != 1 {
+		t.Errorf("len(Frames.Resolve() matches) = %d, want 1", got)
+	}
+
+	// Forest is invalidated by Resolve.
+	if got := frames.Forest().Heads(nil); len(got) != 1 {
+		t.Fatalf("Frames.Forest().Heads() = %v, want [is]", got)
+	}
+
+	frames.Resolve(Coreference{
+		Pronouns:     []string{"it", "they"},
+		Descriptions: []string{"this nation"},
+		Window:       2,
+	})
+
+	want := map[int]match{
+		0: {id: "de", alias: "Germany"},
+		4: {id: "de", alias: "Germany", coref: true},
+		7: {id: "de", alias: "Germany", coref: true},
+	}
+	if diff := cmp.Diff(want, frames.frames[0].matches, cmp.AllowUnexported(match{})); diff != "" {
+		t.Errorf("Frames.Resolve() matches mismatch (-want +got):\n%s", diff)
+	}
+
+	heads := frames.Forest().Heads(nil)
+	if diff := cmp.Diff([]*tokenize.Token{tokens[1], tokens[5], tokens[9]}, heads); diff != "" {
+		t.Errorf("Frames.Forest().Heads() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_compatible(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		pronoun, entity testhelper.PoSParams
+		want            bool
+	}{
+		{
+			name:    "unknown",
+			pronoun: testhelper.PoSParams{},
+			entity:  testhelper.PoSParams{},
+			want:    true,
+		},
+		{
+			name:    "number mismatch",
+			pronoun: testhelper.PoSParams{Number: tokenize.PartOfSpeechNumberPlural},
+			entity:  testhelper.PoSParams{Number: tokenize.PartOfSpeechNumberSingular},
+			want:    false,
+		},
+		{
+			name:    "gender mismatch",
+			pronoun: testhelper.PoSParams{Gender: tokenize.PartOfSpeechGenderFeminine},
+			entity:  testhelper.PoSParams{Gender: tokenize.PartOfSpeechGenderMasculine},
+			want:    false,
+		},
+		{
+			name:    "first person",
+			pronoun: testhelper.PoSParams{Person: tokenize.PartOfSpeechPersonFirst},
+			entity:  testhelper.PoSParams{},
+			want:    false,
+		},
+		{
+			name:    "third person",
+			pronoun: testhelper.PoSParams{Person: tokenize.PartOfSpeechPersonThird},
+			entity:  testhelper.PoSParams{},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				pronoun = &tokenize.Token{PartOfSpeech: testhelper.NewPoS(t, tokenize.PartOfSpeechTagPron, tt.pronoun)}
+				entity  = &tokenize.Token{PartOfSpeech: testhelper.NewPoS(t, tokenize.PartOfSpeechTagNoun, tt.entity)}
+			)
+			if got := compatible(pronoun, entity); got != tt.want {
+				t.Errorf("compatible() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@ package entitydebs
 
 import (
 	"iter"
-	"math"
+	"maps"
 	"slices"
 
 	"github.com/ndabAP/entitydebs/dependency"
@@ -16,6 +16,8 @@ type (
 
 		// entities contains all entity tokens of all frames.
 		entities []*tokenize.Token
+		// ids contains the entity ID of every entity token.
+		ids map[*tokenize.Token]string
 	}
 )
 
@@ -30,37 +32,31 @@ func (f *Frames) Forest() deps {
 	deps := deps{
 		forest:   make([]dependency.Tree, 0),
 		entities: make([]*tokenize.Token, 0),
+		ids:      make(map[*tokenize.Token]string),
 	}
 
 	// Accumulate all entities of all frames.
 	for _, frame := range f.frames {
-		for _, tokens := range frame.entities {
+		// Sort offsets for a deterministic order.
+		for _, offset := range slices.Sorted(maps.Keys(frame.entities)) {
+			tokens := frame.entities[offset]
 			deps.entities = append(deps.entities, tokens...)
+			for _, token := range tokens {
+				deps.ids[token] = frame.matches[offset].id
+			}
 		}
 	}
 
-	// Accumulate all dependency trees of all frames.
-	var offset int32
-	for index, tokens := range f.All() {
-		if index == 0 {
-			// Next frame, reset offset.
-			offset = 0
-		}
-
+	// Accumulate all dependency trees of all frames. Head token indices are
+	// relative to the frame, so the offset of a tree is the token offset of its
+	// sentence.
+	for offset, tokens := range f.All() {
 		// We are looking for trees that contain entity tokens.
 		if !slices.ContainsFunc(tokens, func(token *tokenize.Token) bool {
 			return slices.Contains(deps.entities, token)
 		}) {
-			// Don't corrupt offset.
-			n := len(tokens)
-			if n > math.MaxInt32 {
-				panic("entitydebs: too many tokens")
-			}
-			offset += int32(n)
-
 			continue
 		}
-		offset += index
 
 		tree := dependency.Parse(offset, tokens)
 		deps.forest = append(deps.forest, tree)
@@ -81,6 +77,32 @@ func (f Frames) Trees() iter.Seq[dependency.Tree] {
 	}
 }
 
+// Entity returns the forest restricted to the entity with id. Only trees that
+// contain tokens of the entity are kept, and only its tokens are treated as
+// entity tokens.
+func (deps deps) Entity(id string) deps {
+	filtered := deps
+	filtered.forest = make([]dependency.Tree, 0)
+	filtered.entities = make([]*tokenize.Token, 0)
+	filtered.ids = make(map[*tokenize.Token]string)
+	for _, token := range deps.entities {
+		if deps.ids[token] != id {
+			continue
+		}
+		filtered.entities = append(filtered.entities, token)
+		filtered.ids[token] = id
+	}
+	for _, tree := range deps.forest {
+		if _, ok := tree.Search(func(token *tokenize.Token) bool {
+			return slices.Contains(filtered.entities, token)
+		}); ok {
+			filtered.forest = append(filtered.forest, tree)
+		}
+	}
+
+	return filtered
+}
+
 // Roots returns all root tokens for every tree.
 func (deps deps) Roots() iter.Seq[*tokenize.Token] {
 	return func(yield func(*tokenize.Token) bool) {
@@ -68,6 +68,53 @@ func TestFramesForest(t *testing.T) {
 			}
 		}
 	})
+	t.Run("one frame, entity in third sentence", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			token = func(offset int32, head int32, label tokenize.DependencyEdgeLabel) *tokenize.Token {
+				return testhelper.NewToken(t, "", offset, nil, testhelper.NewDepEdge(t, head, label), "")
+			}
+			// Head token indices are relative to the frame.
+			tokens = []*tokenize.Token{
+				// Spain grows.
+				token(0, 1, tokenize.DependencyEdgeLabelNSubj),
+				token(6, 1, tokenize.DependencyEdgeLabelRoot),
+				token(11, 1, tokenize.DependencyEdgeLabelP),
+				// France shrinks.
+				token(13, 4, tokenize.DependencyEdgeLabelNSubj),
+				token(20, 4, tokenize.DependencyEdgeLabelRoot),
+				token(27, 4, tokenize.DependencyEdgeLabelP),
+				// Germany stays.
+				token(29, 7, tokenize.DependencyEdgeLabelNSubj),
+				token(37, 7, tokenize.DependencyEdgeLabelRoot),
+				token(42, 7, tokenize.DependencyEdgeLabelP),
+			}
+			frames = Frames{
+				frames: []frame{
+					{
+						sentences: []*tokenize.Sentence{
+							testhelper.NewSentence(t, "", 0, nil),
+							testhelper.NewSentence(t, "", 13, nil),
+							testhelper.NewSentence(t, "", 29, nil),
+						},
+						tokens: tokens,
+						entities: map[int][]*tokenize.Token{
+							6: {tokens[6]},
+						},
+					},
+				},
+			}
+		)
+
+		forest := frames.Forest()
+		if diff := cmp.Diff([]*tokenize.Token{tokens[7]}, slices.Collect(forest.Roots())); diff != "" {
+			t.Errorf("Frames.Forest().Roots() mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]*tokenize.Token{tokens[7]}, forest.Heads(nil)); diff != "" {
+			t.Errorf("Frames.Forest().Heads() mismatch (-want +got):\n%s", diff)
+		}
+	})
 	t.Run("two frames, (multi token) entities in both", func(t *testing.T) {
 		t.Parallel()
 
@@ -443,3 +490,78 @@ func Test_depsRelationships(t *testing.T) {
 		})
 	}
 }
+
+func Test_depsEntity(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sentence1 = testhelper.NewExampleSentence1(t, 0)
+		tokens1   = testhelper.NewExampleTokens1(t, 0, 0)
+		sentence2 = testhelper.NewExampleSentence2(t, 0)
+		tokens2   = testhelper.NewExampleTokens2(t, 0, 0)
+
+		frames = Frames{
+			frames: []frame{
+				{
+					sentences: []*tokenize.Sentence{sentence1},
+					tokens:    tokens1,
+					entities: map[int][]*tokenize.Token{
+						6: {tokens1[6]}, // Denver
+					},
+					matches: map[int]match{
+						6: {id: "denver", alias: "Denver"},
+					},
+				},
+				{
+					sentences: []*tokenize.Sentence{sentence2},
+					tokens:    tokens2,
+					entities: map[int][]*tokenize.Token{
+						5: {tokens2[5]}, // Houston
+					},
+					matches: map[int]match{
+						5: {id: "houston", alias: "Houston"},
+					},
+				},
+			},
+		}
+	)
+
+	tests := []struct {
+		id       string
+		entities []*tokenize.Token
+		heads    []*tokenize.Token
+	}{
+		{
+			id:       "denver",
+			entities: []*tokenize.Token{tokens1[6]},
+			heads:    []*tokenize.Token{tokens1[5]}, // through
+		},
+		{
+			id:       "houston",
+			entities: []*tokenize.Token{tokens2[5]},
+			heads:    []*tokenize.Token{tokens2[4]}, // through
+		},
+		{
+			id:       "unknown",
+			entities: nil,
+			heads:    []*tokenize.Token{},
+		},
+	}
+	deps := frames.Forest()
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+
+			forest := deps.Entity(tt.id)
+			if diff := cmp.Diff(tt.entities, slices.Collect(forest.Entities())); diff != "" {
+				t.Errorf("deps.Entity().Entities() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.heads, forest.Heads(nil)); diff != "" {
+				t.Errorf("deps.Entity().Heads() mismatch (-want +got):\n%s", diff)
+			}
+			if n := len(forest.forest); n != len(tt.entities) {
+				t.Errorf("len(deps.Entity().forest) = %d, want %d", n, len(tt.entities))
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package entitydebs
+
+import (
+	"iter"
+	"maps"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+// Frame is a read-only view of a data frame, e.g., for predicates of
+// [Frames.Filter].
+type Frame struct {
+	// Index is the index of the text of the frame.
+	Index int
+	// Metadata contains the metadata of the text, see [WithMetadata].
+	Metadata map[string]string
+	// Language is the language of the text, if known.
+	Language string
+	// Sentiment is the document sentiment, if analyzed.
+	Sentiment *tokenize.Sentiment
+	// Sentences contains all sentences of the frame.
+	Sentences []*tokenize.Sentence
+	// Tokens contains all tokens of the frame.
+	Tokens []*tokenize.Token
+	// Mentions is the number of entity mentions within the frame.
+	Mentions int
+}
+
+// view returns the read-only view of f.
+func (f frame) view() Frame {
+	return Frame{
+		Index:     f.index,
+		Metadata:  f.metadata,
+		Language:  f.language,
+		Sentiment: f.sentiment,
+		Sentences: f.sentences,
+		Tokens:    f.tokens,
+		Mentions:  len(f.matches),
+	}
+}
+
+// Frames returns the read-only views of all frames.
+func (f Frames) Frames() iter.Seq2[int, Frame] {
+	return func(yield func(int, Frame) bool) {
+		for i, frame := range f.frames {
+			if !yield(i, frame.view()) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns new [Frames] of all frames for which fn returns true.
+func (f Frames) Filter(fn func(Frame) bool) Frames {
+	frames := make([]frame, 0)
+	for _, frame := range f.frames {
+		if fn(frame.view()) {
+			frames = append(frames, frame)
+		}
+	}
+
+	return f.derive(frames)
+}
+
+// Slice returns new [Frames] of the frames i through j-1. Like slicing, it
+// panics if the indices are out of range.
+func (f Frames) Slice(i, j int) Frames {
+	return f.derive(slices.Clone(f.frames[i:j]))
+}
+
+// Sample returns new [Frames] of n uniformly sampled frames without
+// replacement, in their original order. The same seed returns the same
+// sample. If n exceeds the number of frames, all frames are returned.
+func (f Frames) Sample(n int, seed uint64) Frames {
+	var (
+		rng     = rand.New(rand.NewPCG(seed, seed))
+		indices = rng.Perm(len(f.frames))[:max(min(n, len(f.frames)), 0)]
+	)
+	slices.Sort(indices)
+
+	frames := make([]frame, 0, len(indices))
+	for _, i := range indices {
+		frames = append(frames, f.frames[i])
+	}
+
+	return f.derive(frames)
+}
+
+// Group returns new [Frames] of frames grouped by the key fn returns. Frames
+// keep their order within groups. Use [Frames.Sample] on every group for
+// stratified samples.
+func (f Frames) Group(fn func(Frame) string) map[string]Frames {
+	groups := make(map[string][]frame)
+	for _, frame := range f.frames {
+		key := fn(frame.view())
+		groups[key] = append(groups[key], frame)
+	}
+
+	derived := make(map[string]Frames, len(groups))
+	for key, frames := range groups {
+		derived[key] = f.derive(frames)
+	}
+
+	return derived
+}
+
+// derive returns new [Frames] of frames with the entities of f and without
+// forest, so every derived [Frames] gets its own forest. Frames don't share
+// mutable state with f, e.g., for [Frames.Resolve].
+func (f Frames) derive(frames []frame) Frames {
+	for i := range frames {
+		frames[i].entities = maps.Clone(frames[i].entities)
+		frames[i].matches = maps.Clone(frames[i].matches)
+	}
+
+	return Frames{
+		frames:   frames,
+		entities: f.entities,
+		ids:      f.ids,
+	}
+}
@@ -0,0 +1,76 @@
+package entitydebs
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFramesFilter(t *testing.T) {
+	t.Parallel()
+
+	frames := Frames{frames: make([]frame, 0)}
+	for i := range 10 {
+		party := "D"
+		if i%2 == 1 {
+			party = "R"
+		}
+		frames.frames = append(frames.frames, frame{
+			index:    i,
+			metadata: map[string]string{"party": party},
+		})
+	}
+	indices := func(frames Frames) []int {
+		indices := make([]int, 0)
+		for _, frame := range frames.Frames() {
+			indices = append(indices, frame.Index)
+		}
+		return indices
+	}
+
+	t.Run("filter", func(t *testing.T) {
+		t.Parallel()
+
+		got := frames.Filter(func(frame Frame) bool {
+			return frame.Metadata["party"] == "R"
+		})
+		if diff := cmp.Diff([]int{1, 3, 5, 7, 9}, indices(got)); diff != "" {
+			t.Errorf("Frames.Filter() mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("slice", func(t *testing.T) {
+		t.Parallel()
+
+		if diff := cmp.Diff([]int{2, 3, 4}, indices(frames.Slice(2, 5))); diff != "" {
+			t.Errorf("Frames.Slice() mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("sample", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := indices(frames.Sample(4, 42)), indices(frames.Sample(4, 42))
+		if diff := cmp.Diff(a, b); diff != "" {
+			t.Errorf("Frames.Sample() not reproducible (-want +got):\n%s", diff)
+		}
+		if len(a) != 4 || !slices.IsSorted(a) {
+			t.Errorf("Frames.Sample() = %v, want 4 ordered frames", a)
+		}
+		if got := len(indices(frames.Sample(20, 42))); got != 10 {
+			t.Errorf("len(Frames.Sample(20)) = %d, want 10", got)
+		}
+	})
+	t.Run("group", func(t *testing.T) {
+		t.Parallel()
+
+		groups := frames.Group(func(frame Frame) string {
+			return frame.Metadata["party"]
+		})
+		if diff := cmp.Diff([]int{0, 2, 4, 6, 8}, indices(groups["D"])); diff != "" {
+			t.Errorf("Frames.Group()[D] mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]int{1, 3, 5, 7, 9}, indices(groups["R"])); diff != "" {
+			t.Errorf("Frames.Group()[R] mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
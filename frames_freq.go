@@ -0,0 +1,256 @@
+package entitydebs
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+	"golang.org/x/text/cases"
+)
+
+type (
+	// Frequencies configures a frequency distribution, see
+	// [Frames.Frequencies].
+	Frequencies struct {
+		// By is the counted token property.
+		By Property
+		// Scope restricts the counted tokens.
+		Scope Scope
+		// Stopwords contains excluded token texts, compared
+		// case-insensitively.
+		Stopwords []string
+		// Window is the number of tokens before and after a mention, for
+		// [ScopeContext]. Zero defaults to 5.
+		Window int
+		// N limits the distribution to the n most frequent keys. Zero keeps
+		// all keys.
+		N int
+	}
+
+	// Property is a token property.
+	Property int
+
+	// Scope is a set of tokens.
+	Scope int
+
+	// Frequency is the frequency of a key.
+	Frequency struct {
+		Key   string
+		Count int
+		// Relative is the count relative to the count of all keys.
+		Relative float64
+	}
+)
+
+const (
+	// PropertyText is the token text. This is the default.
+	PropertyText Property = iota
+	// PropertyLemma is the token lemma, or the text if it has no lemma.
+	PropertyLemma
+	// PropertyTag is the part of speech tag, e.g., NOUN.
+	PropertyTag
+	// PropertyLabel is the dependency edge label, e.g., NSUBJ.
+	PropertyLabel
+)
+
+const (
+	// ScopeAll contains all tokens of all frames. This is the default.
+	ScopeAll Scope = iota
+	// ScopeHeads contains the heads of entities, see [Frames.Forest].
+	ScopeHeads
+	// ScopeDependents contains the dependents of entities, see
+	// [Frames.Forest].
+	ScopeDependents
+	// ScopeContext contains the tokens within a window around mentions
+	// within their sentence.
+	ScopeContext
+)
+
+// Frequencies returns the frequency distribution of a token property,
+// ordered by descending count and then by key.
+func (f *Frames) Frequencies(freqs Frequencies) []Frequency {
+	counts, total := count(f.scope(freqs.Scope, "", freqs.Window), freqs.By, newStoplist(freqs.Stopwords))
+	frequencies := make([]Frequency, 0, len(counts))
+	for _, key := range slices.Sorted(maps.Keys(counts)) {
+		frequencies = append(frequencies, Frequency{
+			Key:      key,
+			Count:    counts[key],
+			Relative: float64(counts[key]) / float64(total),
+		})
+	}
+	slices.SortStableFunc(frequencies, func(a, b Frequency) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	if freqs.N > 0 && len(frequencies) > freqs.N {
+		frequencies = frequencies[:freqs.N]
+	}
+
+	return frequencies
+}
+
+// count returns the number of tokens per property and the total number of
+// counted tokens.
+func count(tokens []*tokenize.Token, by Property, stopwords stoplist) (map[string]int, int) {
+	var (
+		counts = make(map[string]int)
+		total  int
+	)
+	for _, token := range tokens {
+		if stopwords.contains(token) {
+			continue
+		}
+		key, ok := by.of(token)
+		if !ok {
+			continue
+		}
+		counts[key]++
+		total++
+	}
+
+	return counts, total
+}
+
+// scope returns the tokens of scope, restricted to the entity with id unless
+// id is empty.
+func (f *Frames) scope(scope Scope, id string, window int) []*tokenize.Token {
+	tokens := make([]*tokenize.Token, 0)
+	switch scope {
+	case ScopeHeads:
+		return f.forest(id).Heads(nil)
+	case ScopeDependents:
+		return f.forest(id).Dependents(nil)
+	case ScopeContext:
+		context := f.contexts(id, window)
+		for _, frame := range f.frames {
+			for _, token := range frame.tokens {
+				if _, ok := context[token]; ok {
+					tokens = append(tokens, token)
+				}
+			}
+		}
+	default:
+		for _, frame := range f.frames {
+			tokens = append(tokens, frame.tokens...)
+		}
+	}
+
+	return tokens
+}
+
+// forest returns the forest, restricted to the entity with id unless id is
+// empty.
+func (f *Frames) forest(id string) deps {
+	if id == "" {
+		return f.Forest()
+	}
+
+	return f.Forest().Entity(id)
+}
+
+// nodes returns the mention tokens of the entity with id, or of all entities
+// if id is empty.
+func (f Frames) nodes(id string) map[*tokenize.Token]struct{} {
+	nodes := make(map[*tokenize.Token]struct{})
+	for mention := range f.Mentions() {
+		if id != "" && mention.Entity != id {
+			continue
+		}
+		for _, token := range mention.Tokens {
+			nodes[token] = struct{}{}
+		}
+	}
+
+	return nodes
+}
+
+// contexts returns the tokens within window tokens before and after the
+// mentions of the entity with id, or of all entities if id is empty. Windows
+// don't cross sentences and don't contain mention tokens. A zero window
+// defaults to 5.
+func (f Frames) contexts(id string, window int) map[*tokenize.Token]struct{} {
+	if window <= 0 {
+		window = 5
+	}
+
+	var (
+		nodes   = f.nodes(id)
+		context = make(map[*tokenize.Token]struct{})
+		indices = make(map[int][]int)
+	)
+	for mention := range f.Mentions() {
+		if id != "" && mention.Entity != id {
+			continue
+		}
+
+		frame := f.frames[mention.Frame]
+		if _, ok := indices[mention.Frame]; !ok {
+			indices[mention.Frame] = frame.sentenceIndices()
+		}
+		sentences := indices[mention.Frame]
+		for i := max(mention.Start-window, 0); i < min(mention.End+window, len(frame.tokens)); i++ {
+			if sentences[i] != mention.Sentence {
+				continue
+			}
+			if _, ok := nodes[frame.tokens[i]]; ok {
+				continue
+			}
+			context[frame.tokens[i]] = struct{}{}
+		}
+	}
+
+	return context
+}
+
+// of returns the property of token, if it has one.
+func (property Property) of(token *tokenize.Token) (string, bool) {
+	switch property {
+	case PropertyLemma:
+		if token.Lemma != "" {
+			return token.Lemma, true
+		}
+	case PropertyTag:
+		if token.PartOfSpeech == nil {
+			return "", false
+		}
+		return token.PartOfSpeech.Tag.String(), true
+	case PropertyLabel:
+		if token.DependencyEdge == nil {
+			return "", false
+		}
+		return token.DependencyEdge.Label.String(), true
+	}
+	if token.Text == nil {
+		return "", false
+	}
+
+	return token.Text.Content, true
+}
+
+// stoplist is a case-insensitive set of stopwords.
+type stoplist struct {
+	fold  cases.Caser
+	words map[string]struct{}
+}
+
+func newStoplist(words []string) stoplist {
+	stoplist := stoplist{
+		fold:  cases.Fold(),
+		words: make(map[string]struct{}, len(words)),
+	}
+	for _, word := range words {
+		stoplist.words[stoplist.fold.String(word)] = struct{}{}
+	}
+
+	return stoplist
+}
+
+// contains returns true if the text of token is a stopword.
+func (stoplist stoplist) contains(token *tokenize.Token) bool {
+	if token.Text == nil || len(stoplist.words) == 0 {
+		return false
+	}
+	_, ok := stoplist.words[stoplist.fold.String(token.Text.Content)]
+
+	return ok
+}
@@ -0,0 +1,122 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/testhelper"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestFramesFrequencies(t *testing.T) {
+	t.Parallel()
+
+	var (
+		token = func(content string, offset int32, tag tokenize.PartOfSpeechTag, head int32, label tokenize.DependencyEdgeLabel, lemma string) *tokenize.Token {
+			return testhelper.NewToken(t,
+				content,
+				offset,
+				testhelper.NewPoS(t, tag, testhelper.PoSParams{}),
+				testhelper.NewDepEdge(t, head, label),
+				lemma,
+			)
+		}
+
+		// Germany is strong and Germany grows.
+		tokens = []*tokenize.Token{
+			token("Germany", 0, tokenize.PartOfSpeechTagNoun, 1, tokenize.DependencyEdgeLabelNSubj, ""),
+			token("is", 8, tokenize.PartOfSpeechTagVerb, 1, tokenize.DependencyEdgeLabelRoot, "be"),
+			token("strong", 11, tokenize.PartOfSpeechTagAdj, 1, tokenize.DependencyEdgeLabelAComp, "strong"),
+			token("and", 18, tokenize.PartOfSpeechTagConj, 1, tokenize.DependencyEdgeLabelCC, "and"),
+			token("Germany", 22, tokenize.PartOfSpeechTagNoun, 5, tokenize.DependencyEdgeLabelNSubj, ""),
+			token("grows", 30, tokenize.PartOfSpeechTagVerb, 1, tokenize.DependencyEdgeLabelConj, "grow"),
+			token(".", 35, tokenize.PartOfSpeechTagPunct, 1, tokenize.DependencyEdgeLabelP, ""),
+		}
+	)
+
+	tests := []struct {
+		name  string
+		freqs Frequencies
+		want  []Frequency
+	}{
+		{
+			name:  "text",
+			freqs: Frequencies{Stopwords: []string{"AND", "."}},
+			want: []Frequency{
+				{Key: "Germany", Count: 2, Relative: 0.4},
+				{Key: "grows", Count: 1, Relative: 0.2},
+				{Key: "is", Count: 1, Relative: 0.2},
+				{Key: "strong", Count: 1, Relative: 0.2},
+			},
+		},
+		{
+			name:  "lemma top n",
+			freqs: Frequencies{By: PropertyLemma, N: 2},
+			want: []Frequency{
+				{Key: "Germany", Count: 2, Relative: 2.0 / 7},
+				{Key: ".", Count: 1, Relative: 1.0 / 7},
+			},
+		},
+		{
+			name:  "tag",
+			freqs: Frequencies{By: PropertyTag},
+			want: []Frequency{
+				{Key: "NOUN", Count: 2, Relative: 2.0 / 7},
+				{Key: "VERB", Count: 2, Relative: 2.0 / 7},
+				{Key: "ADJ", Count: 1, Relative: 1.0 / 7},
+				{Key: "CONJ", Count: 1, Relative: 1.0 / 7},
+				{Key: "PUNCT", Count: 1, Relative: 1.0 / 7},
+			},
+		},
+		{
+			name:  "heads",
+			freqs: Frequencies{By: PropertyLemma, Scope: ScopeHeads},
+			want: []Frequency{
+				{Key: "be", Count: 1, Relative: 0.5},
+				{Key: "grow", Count: 1, Relative: 0.5},
+			},
+		},
+		{
+			name:  "context",
+			freqs: Frequencies{Scope: ScopeContext, Window: 1},
+			want: []Frequency{
+				{Key: "and", Count: 1, Relative: 1.0 / 3},
+				{Key: "grows", Count: 1, Relative: 1.0 / 3},
+				{Key: "is", Count: 1, Relative: 1.0 / 3},
+			},
+		},
+		{
+			name:  "empty",
+			freqs: Frequencies{Scope: ScopeDependents},
+			want:  []Frequency{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			frames := Frames{
+				frames: []frame{
+					{
+						sentences: []*tokenize.Sentence{
+							testhelper.NewSentence(t, "Germany is strong and Germany grows.", 0, nil),
+						},
+						tokens: tokens,
+						entities: map[int][]*tokenize.Token{
+							0: {tokens[0]},
+							4: {tokens[4]},
+						},
+						matches: map[int]match{
+							0: {id: "de", alias: "Germany"},
+							4: {id: "de", alias: "Germany"},
+						},
+					},
+				},
+			}
+			got := frames.Frequencies(test.freqs)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Frames.Frequencies() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
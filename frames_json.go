@@ -2,31 +2,179 @@ package entitydebs
 
 import (
 	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/ndabAP/entitydebs/tokenize"
 )
 
+// framesVersion is the version of the JSON schema of [Frames].
+const framesVersion = 1
+
+// framesJSON is the JSON representation of Frames.
+type framesJSON struct {
+	Version  int                         `json:"version"`
+	Frames   []frameJSON                 `json:"frames"`
+	Entities map[string][]tokenize.Token `json:"entities"`
+	IDs      map[string]string           `json:"ids"`
+}
+
+// MarshalJSON encodes f as versioned JSON, including the entity mentions and
+// metadata of all frames.
 func (f Frames) MarshalJSON() ([]byte, error) {
-	type frame struct {
-		Sentences []*tokenize.Sentence `json:"sentences"`
-		Tokens    []*tokenize.Token    `json:"tokens"`
-		Sentiment *tokenize.Sentiment  `json:"sentiment"`
-	}
-
-	// Serialize frames.
-	frames := make([]frame, 0, len(f.frames))
-	for _, f := range f.frames {
-		frames = append(frames, frame{
-			Sentences: f.sentences,
-			Tokens:    f.tokens,
-			Sentiment: f.sentiment,
-		})
+	frames := make([]frameJSON, 0, len(f.frames))
+	for _, frame := range f.frames {
+		frames = append(frames, frame.marshal())
 	}
-	return json.Marshal(struct {
-		Frames   []frame                     `json:"frames"`
-		Entities map[string][]tokenize.Token `json:"entities"`
-	}{
+
+	return json.Marshal(framesJSON{
+		Version:  framesVersion,
 		Frames:   frames,
 		Entities: f.entities,
+		IDs:      f.ids,
 	})
 }
+
+// UnmarshalJSON decodes f from JSON encoded by [Frames.MarshalJSON]. The
+// forest is computed anew.
+func (f *Frames) UnmarshalJSON(b []byte) error {
+	var fj framesJSON
+	if err := json.Unmarshal(b, &fj); err != nil {
+		return err
+	}
+	if fj.Version != framesVersion {
+		return fmt.Errorf("entitydebs: unsupported frames version %d", fj.Version)
+	}
+
+	frames := make([]frame, 0, len(fj.Frames))
+	for i, frameJSON := range fj.Frames {
+		frame, err := frameJSON.unmarshal()
+		if err != nil {
+			return fmt.Errorf("entitydebs: frame %d: %w", i, err)
+		}
+		frames = append(frames, frame)
+	}
+
+	*f = Frames{
+		frames:   frames,
+		entities: fj.Entities,
+		ids:      fj.IDs,
+	}
+
+	return nil
+}
+
+type (
+	// frameJSON is the JSON representation of a frame.
+	frameJSON struct {
+		Index      int                  `json:"index"`
+		Metadata   map[string]string    `json:"metadata,omitempty"`
+		Language   string               `json:"language,omitempty"`
+		Sentences  []*tokenize.Sentence `json:"sentences"`
+		Tokens     []*tokenize.Token    `json:"tokens"`
+		Sentiment  *tokenize.Sentiment  `json:"sentiment"`
+		Mentions   []mentionJSON        `json:"mentions"`
+		Rejections []mentionJSON        `json:"rejections,omitempty"`
+	}
+
+	// mentionJSON is the JSON representation of a match or rejection of n
+	// tokens at offset.
+	mentionJSON struct {
+		Offset int    `json:"offset"`
+		Length int    `json:"length"`
+		Entity string `json:"entity"`
+		Alias  string `json:"alias"`
+		Coref  bool   `json:"coref,omitempty"`
+		Reason Reason `json:"reason,omitempty"`
+	}
+)
+
+// marshal returns the JSON representation of f. Mentions are ordered by
+// offset.
+func (f frame) marshal() frameJSON {
+	mentions := make([]mentionJSON, 0, len(f.matches))
+	for _, offset := range slices.Sorted(maps.Keys(f.matches)) {
+		match := f.matches[offset]
+		mentions = append(mentions, mentionJSON{
+			Offset: offset,
+			Length: len(f.entities[offset]),
+			Entity: match.id,
+			Alias:  match.alias,
+			Coref:  match.coref,
+		})
+	}
+	rejections := make([]mentionJSON, 0, len(f.rejections))
+	for _, rejection := range f.rejections {
+		rejections = append(rejections, mentionJSON{
+			Offset: rejection.Offset,
+			Length: len(rejection.Tokens),
+			Entity: rejection.Entity,
+			Alias:  rejection.Alias,
+			Reason: rejection.Reason,
+		})
+	}
+
+	return frameJSON{
+		Index:      f.index,
+		Metadata:   f.metadata,
+		Language:   f.language,
+		Sentences:  f.sentences,
+		Tokens:     f.tokens,
+		Sentiment:  f.sentiment,
+		Mentions:   mentions,
+		Rejections: rejections,
+	}
+}
+
+// unmarshal returns the frame of the JSON representation. Entity tokens refer
+// to the frame tokens. Callers prefix errors with the package and frame.
+func (fj frameJSON) unmarshal() (frame, error) {
+	f := frame{
+		index:     fj.Index,
+		metadata:  fj.Metadata,
+		language:  fj.Language,
+		sentences: fj.Sentences,
+		tokens:    fj.Tokens,
+		sentiment: fj.Sentiment,
+		entities:  make(map[int][]*tokenize.Token, len(fj.Mentions)),
+		matches:   make(map[int]match, len(fj.Mentions)),
+	}
+	if f.sentences == nil {
+		f.sentences = make([]*tokenize.Sentence, 0)
+	}
+	if f.tokens == nil {
+		f.tokens = make([]*tokenize.Token, 0)
+	}
+
+	for _, mention := range fj.Mentions {
+		if !mention.valid(len(f.tokens)) {
+			return f, fmt.Errorf("invalid mention at offset %d", mention.Offset)
+		}
+		f.entities[mention.Offset] = slices.Clone(f.tokens[mention.Offset : mention.Offset+mention.Length])
+		f.matches[mention.Offset] = match{
+			id:    mention.Entity,
+			alias: mention.Alias,
+			coref: mention.Coref,
+		}
+	}
+	for _, rejection := range fj.Rejections {
+		if !rejection.valid(len(f.tokens)) {
+			return f, fmt.Errorf("invalid rejection at offset %d", rejection.Offset)
+		}
+		f.rejections = append(f.rejections, Rejection{
+			Offset: rejection.Offset,
+			Entity: rejection.Entity,
+			Alias:  rejection.Alias,
+			Tokens: f.tokens[rejection.Offset : rejection.Offset+rejection.Length],
+			Reason: rejection.Reason,
+		})
+	}
+
+	return f, nil
+}
+
+// valid reports whether the mention is within n tokens.
+func (mention mentionJSON) valid(n int) bool {
+	return mention.Offset >= 0 && mention.Length > 0 && mention.Offset+mention.Length <= n
+}
@@ -0,0 +1,118 @@
+package entitydebs
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/testhelper"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestFramesUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	frames := newExampleFrames(t)
+
+	b, err := json.Marshal(frames)
+	if err != nil {
+		t.Fatalf("Frames.MarshalJSON() = _, %s, want nil", err)
+	}
+	var got Frames
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Frames.UnmarshalJSON() = %s, want nil", err)
+	}
+
+	// Round trip
+	if b2, _ := json.Marshal(got); !bytes.Equal(b, b2) {
+		t.Errorf("Frames.UnmarshalJSON() = %s, want %s", b2, b)
+	}
+	if diff := cmp.Diff(slices.Collect(frames.Rejections()), slices.Collect(got.Rejections())); diff != "" {
+		t.Errorf("Frames.Rejections() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Forest
+	contents := func(tokens []*tokenize.Token) []string {
+		s := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			s = append(s, token.Text.Content)
+		}
+		slices.Sort(s)
+		return s
+	}
+	if diff := cmp.Diff(contents(frames.Forest().Heads(nil)), contents(got.Forest().Heads(nil))); diff != "" {
+		t.Errorf("Frames.Forest().Heads() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(contents(frames.Forest().Dependents(nil)), contents(got.Forest().Dependents(nil))); diff != "" {
+		t.Errorf("Frames.Forest().Dependents() mismatch (-want +got):\n%s", diff)
+	}
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		var frames Frames
+		if err := json.Unmarshal([]byte(`{"version":0}`), &frames); err == nil {
+			t.Error("Frames.UnmarshalJSON() = nil, want error")
+		}
+	})
+	t.Run("invalid mention", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			frames Frames
+			b      = []byte(`{"version":1,"frames":[{"mentions":[{"offset":3,"length":1}]}]}`)
+			want   = "entitydebs: frame 0: invalid mention at offset 3"
+		)
+		if err := json.Unmarshal(b, &frames); err == nil || err.Error() != want {
+			t.Errorf("Frames.UnmarshalJSON() = %v, want %s", err, want)
+		}
+	})
+}
+
+// newExampleFrames returns frames of two example texts with mentions,
+// rejections and metadata.
+func newExampleFrames(t *testing.T) Frames {
+	t.Helper()
+
+	var (
+		sentence1 = testhelper.NewExampleSentence1(t, 0)
+		tokens1   = testhelper.NewExampleTokens1(t, 0, 0)
+		sentence2 = testhelper.NewExampleSentence2(t, 0)
+		tokens2   = testhelper.NewExampleTokens2(t, 0, 0)
+	)
+	return Frames{
+		frames: []frame{
+			{
+				index:     0,
+				metadata:  map[string]string{"party": "D"},
+				sentences: []*tokenize.Sentence{sentence1},
+				tokens:    tokens1,
+				sentiment: &tokenize.Sentiment{Score: 0.5, Magnitude: 1},
+				entities: map[int][]*tokenize.Token{
+					5: {tokens1[5], tokens1[6]}, // through Denver
+				},
+				matches: map[int]match{
+					5: {id: "denver", alias: "through Denver"},
+				},
+				rejections: []Rejection{
+					{Offset: 4, Entity: "denver", Alias: "flight", Tokens: tokens1[4:5], Reason: ReasonProper},
+				},
+			},
+			{
+				index:     2,
+				sentences: []*tokenize.Sentence{sentence2},
+				tokens:    tokens2,
+				entities: map[int][]*tokenize.Token{
+					5: {tokens2[5]}, // Houston
+				},
+				matches: map[int]match{
+					5: {id: "houston", alias: "Houston", coref: true},
+				},
+			},
+		},
+		entities: map[string][]tokenize.Token{"Houston": {*tokens2[5]}},
+		ids:      map[string]string{"Houston": "houston"},
+	}
+}
@@ -0,0 +1,126 @@
+package entitydebs
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+)
+
+type (
+	// Keyness configures a keyness comparison between two [Frames], see
+	// [Frames.Keyness].
+	Keyness struct {
+		// Entity restricts mentions to the entity with the ID. Empty
+		// considers all entities.
+		Entity string
+		// By is the compared token property, usually [PropertyLemma].
+		By Property
+		// Scope is the compared set of tokens, e.g., [ScopeHeads].
+		Scope Scope
+		// Window is the number of tokens before and after a mention, for
+		// [ScopeContext]. Zero defaults to 5.
+		Window int
+		// Stopwords contains excluded token texts, compared
+		// case-insensitively.
+		Stopwords []string
+		// MinFrequency is the minimum frequency of a key within both
+		// [Frames].
+		MinFrequency int
+	}
+
+	// Keyword is the keyness of a key.
+	Keyword struct {
+		Key string
+		// Target and Reference are the frequencies of the key within the
+		// target and reference [Frames].
+		Target, Reference int
+		// LogLikelihood is the log-likelihood ratio G².
+		LogLikelihood float64
+		// ChiSquare is Pearson's chi-square statistic.
+		ChiSquare float64
+		// LogRatio is the binary logarithm of the ratio of relative
+		// frequencies. It's positive if the key is more frequent within the
+		// target. Zero frequencies are replaced by 0.5.
+		LogRatio float64
+		// P is the p-value of the log-likelihood ratio, with one degree of
+		// freedom.
+		P float64
+	}
+)
+
+// Keyness compares the keys of f, the target, with the keys of reference. The
+// keywords of both are returned, ordered by log-likelihood in descending order
+// and then by key. The sign of the log-ratio tells which [Frames] a keyword is
+// distinctive of.
+func (f *Frames) Keyness(reference *Frames, keyness Keyness) []Keyword {
+	var (
+		stopwords = newStoplist(keyness.Stopwords)
+		a, c      = count(f.scope(keyness.Scope, keyness.Entity, keyness.Window), keyness.By, stopwords)
+		b, d      = count(reference.scope(keyness.Scope, keyness.Entity, keyness.Window), keyness.By, stopwords)
+		keys      = slices.Collect(maps.Keys(a))
+	)
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	keywords := make([]Keyword, 0, len(keys))
+	for _, key := range keys {
+		if a[key]+b[key] < keyness.MinFrequency {
+			continue
+		}
+
+		g2 := logLikelihood(a[key], b[key], c-a[key], d-b[key])
+		keywords = append(keywords, Keyword{
+			Key:           key,
+			Target:        a[key],
+			Reference:     b[key],
+			LogLikelihood: g2,
+			ChiSquare:     chiSquare(a[key], b[key], c-a[key], d-b[key]),
+			LogRatio:      logRatio(a[key], b[key], c, d),
+			P:             math.Erfc(math.Sqrt(g2 / 2)),
+		})
+	}
+	slices.SortStableFunc(keywords, func(a, b Keyword) int {
+		return cmp.Compare(b.LogLikelihood, a.LogLikelihood)
+	})
+
+	return keywords
+}
+
+// chiSquare returns Pearson's chi-square statistic of the contingency table
+//
+//	o11 o12
+//	o21 o22
+func chiSquare(o11, o12, o21, o22 int) float64 {
+	var (
+		n   = float64(o11 + o12 + o21 + o22)
+		den = float64(o11+o12) * float64(o21+o22) * float64(o11+o21) * float64(o12+o22)
+	)
+	if den == 0 {
+		return 0
+	}
+	diff := float64(o11)*float64(o22) - float64(o12)*float64(o21)
+
+	return n * diff * diff / den
+}
+
+// logRatio returns the binary logarithm of the ratio of the relative
+// frequencies a/c and b/d.
+func logRatio(a, b, c, d int) float64 {
+	if c == 0 || d == 0 {
+		return 0
+	}
+	x, y := float64(a), float64(b)
+	if x == 0 {
+		x = 0.5
+	}
+	if y == 0 {
+		y = 0.5
+	}
+
+	return math.Log2((x / float64(c)) / (y / float64(d)))
+}
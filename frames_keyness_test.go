@@ -0,0 +1,66 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestFramesKeyness(t *testing.T) {
+	t.Parallel()
+
+	entities := []Entity{{ID: "us", Aliases: []string{"US"}}}
+
+	tests := []struct {
+		name    string
+		keyness Keyness
+		want    []Keyword
+	}{
+		{
+			name:    "context",
+			keyness: Keyness{Scope: ScopeContext, Window: 2},
+			want: []Keyword{
+				{Key: "weak", Target: 0, Reference: 1, LogLikelihood: 2.4598927154056973, ChiSquare: 2.25, LogRatio: -2, P: 0.11678675863350771},
+				{Key: "rich", Target: 1, Reference: 0, LogLikelihood: 0.8722432187789176, ChiSquare: 0.5625, LogRatio: 0, P: 0.3503350385846503},
+				{Key: "strong", Target: 1, Reference: 0, LogLikelihood: 0.8722432187789176, ChiSquare: 0.5625, LogRatio: 0, P: 0.3503350385846503},
+				{Key: "The", Target: 2, Reference: 1, P: 1},
+				{Key: "is", Target: 2, Reference: 1, P: 1},
+			},
+		},
+		{
+			name:    "min frequency",
+			keyness: Keyness{Entity: "us", Scope: ScopeContext, Window: 2, Stopwords: []string{"the"}, MinFrequency: 2},
+			want: []Keyword{
+				{Key: "is", Target: 2, Reference: 1, P: 1},
+			},
+		},
+		{
+			name:    "unknown entity",
+			keyness: Keyness{Entity: "uk", Scope: ScopeContext},
+			want:    []Keyword{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := NewMultiSource(entities, []string{"The US is strong.", "The US is rich."}).
+				Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax)
+			if err != nil {
+				t.Fatalf("source.Frames() = _, %s, want nil", err)
+			}
+			reference, err := NewMultiSource(entities, []string{"The US is weak."}).
+				Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax)
+			if err != nil {
+				t.Fatalf("source.Frames() = _, %s, want nil", err)
+			}
+
+			got := target.Keyness(&reference, test.keyness)
+			if diff := cmp.Diff(test.want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("Frames.Keyness() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package entitydebs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+	"golang.org/x/text/cases"
+)
+
+type (
+	// KWIC configures a keyword-in-context concordance, see
+	// [Frames.Concordance].
+	KWIC struct {
+		// Left and Right are the context windows before and after a mention,
+		// in tokens, or in characters if Chars is set.
+		Left, Right int
+		// Chars measures the windows in characters instead of tokens.
+		Chars bool
+		// Sort is the order of concordance lines.
+		Sort Sort
+	}
+
+	// Sort is the order of concordance lines.
+	Sort int
+
+	// Concordance contains the concordance lines of all mentions.
+	Concordance []Line
+
+	// Line is a concordance line of a mention.
+	Line struct {
+		Mention Mention
+		// Left and Right are the contexts before and after the mention.
+		Left, Right string
+		// Keyword is the mention text.
+		Keyword string
+	}
+)
+
+const (
+	// SortText orders lines by their position in the texts. This is the
+	// default.
+	SortText Sort = iota
+	// SortLeft orders lines by their left context, starting with the word
+	// closest to the mention.
+	SortLeft
+	// SortRight orders lines by their right context, starting with the word
+	// closest to the mention.
+	SortRight
+)
+
+// concordanceTemplate is the HTML template of a concordance.
+var concordanceTemplate = template.Must(template.New("concordance").Parse(`<table class="kwic">
+<thead><tr><th>Frame</th><th>Entity</th><th>Left</th><th>Keyword</th><th>Right</th></tr></thead>
+<tbody>
+{{- range .}}
+<tr><td>{{.Mention.Frame}}</td><td>{{.Mention.Entity}}</td><td class="left">{{.Left}}</td><td class="keyword"><mark>{{.Keyword}}</mark></td><td class="right">{{.Right}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+`))
+
+// Concordance returns the keyword-in-context concordance of all mentions.
+// Contexts don't cross frames. Tokens are joined according to their offsets,
+// or by a single space if offsets are unavailable.
+func (f Frames) Concordance(kwic KWIC) Concordance {
+	concordance := make(Concordance, 0)
+	for mention := range f.Mentions() {
+		tokens := f.frames[mention.Frame].tokens
+
+		line := Line{
+			Mention: mention,
+			Keyword: join(tokens[mention.Start:mention.End]),
+		}
+		switch {
+		case kwic.Chars:
+			line.Left = lastRunes(join(tokens[:mention.Start]), kwic.Left)
+			line.Right = firstRunes(join(tokens[mention.End:]), kwic.Right)
+		default:
+			line.Left = join(tokens[max(mention.Start-kwic.Left, 0):mention.Start])
+			line.Right = join(tokens[mention.End:min(mention.End+kwic.Right, len(tokens))])
+		}
+		concordance = append(concordance, line)
+	}
+
+	fold := cases.Fold()
+	switch kwic.Sort {
+	case SortLeft:
+		slices.SortStableFunc(concordance, func(a, b Line) int {
+			x, y := strings.Fields(fold.String(a.Left)), strings.Fields(fold.String(b.Left))
+			slices.Reverse(x)
+			slices.Reverse(y)
+			return slices.Compare(x, y)
+		})
+	case SortRight:
+		slices.SortStableFunc(concordance, func(a, b Line) int {
+			return slices.Compare(strings.Fields(fold.String(a.Right)), strings.Fields(fold.String(b.Right)))
+		})
+	}
+
+	return concordance
+}
+
+// WriteText writes the concordance as plain text with aligned keywords.
+func (c Concordance) WriteText(w io.Writer) error {
+	width := 0
+	for _, line := range c {
+		width = max(width, utf8.RuneCountInString(line.Left))
+	}
+	for _, line := range c {
+		pad := strings.Repeat(" ", width-utf8.RuneCountInString(line.Left))
+		if _, err := fmt.Fprintf(w, "%s%s  %s  %s\n", pad, line.Left, line.Keyword, line.Right); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteCSV writes the concordance as CSV with a header.
+func (c Concordance) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"frame", "sentence", "entity", "alias", "left", "keyword", "right"}); err != nil {
+		return err
+	}
+	for _, line := range c {
+		if err := cw.Write([]string{
+			strconv.Itoa(line.Mention.Frame),
+			strconv.Itoa(line.Mention.Sentence),
+			line.Mention.Entity,
+			line.Mention.Alias,
+			line.Left,
+			line.Keyword,
+			line.Right,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteHTML writes the concordance as an HTML table. The keyword is marked.
+func (c Concordance) WriteHTML(w io.Writer) error {
+	return concordanceTemplate.Execute(w, c)
+}
+
+// join joins tokens. Tokens are separated by a space if there is a gap
+// between their offsets, or if offsets are unavailable.
+func join(tokens []*tokenize.Token) string {
+	var sb strings.Builder
+	for i, token := range tokens {
+		if token.Text == nil {
+			continue
+		}
+		if i > 0 && sb.Len() > 0 {
+			prev := tokens[i-1]
+			if prev.Text == nil || tokenBegin(token) != tokenEnd(prev) || tokenBegin(token) == 0 {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(token.Text.Content)
+	}
+
+	return sb.String()
+}
+
+// lastRunes returns the last n runes of s.
+func lastRunes(s string, n int) string {
+	skip := utf8.RuneCountInString(s) - max(n, 0)
+	if skip <= 0 {
+		return s
+	}
+	for i := range s {
+		if skip == 0 {
+			return s[i:]
+		}
+		skip--
+	}
+
+	return ""
+}
+
+// firstRunes returns the first n runes of s.
+func firstRunes(s string, n int) string {
+	i := 0
+	for k := range s {
+		if i == n {
+			return s[:k]
+		}
+		i++
+	}
+
+	return s
+}
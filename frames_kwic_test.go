@@ -0,0 +1,116 @@
+package entitydebs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestFramesConcordance(t *testing.T) {
+	t.Parallel()
+
+	src := NewSource([]string{"US"}, []string{
+		"Yesterday the US signed the treaty.",
+		"Critics say the US lied, again.",
+		"Alone: US.",
+	})
+	frames, err := src.Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	type line struct{ Left, Keyword, Right string }
+	lines := func(concordance Concordance) []line {
+		l := make([]line, 0, len(concordance))
+		for _, c := range concordance {
+			l = append(l, line{c.Left, c.Keyword, c.Right})
+		}
+		return l
+	}
+
+	tests := []struct {
+		name string
+		kwic KWIC
+		want []line
+	}{
+		{
+			name: "tokens",
+			kwic: KWIC{Left: 2, Right: 2},
+			want: []line{
+				{"Yesterday the", "US", "signed the"},
+				{"say the", "US", "lied,"},
+				{"Alone:", "US", "."},
+			},
+		},
+		{
+			name: "characters",
+			kwic: KWIC{Left: 5, Right: 4, Chars: true},
+			want: []line{
+				{"y the", "US", "sign"},
+				{"y the", "US", "lied"},
+				{"lone:", "US", "."},
+			},
+		},
+		{
+			name: "sort left",
+			kwic: KWIC{Left: 2, Right: 1, Sort: SortLeft},
+			want: []line{
+				{"Alone:", "US", "."},
+				{"say the", "US", "lied"},
+				{"Yesterday the", "US", "signed"},
+			},
+		},
+		{
+			name: "sort right",
+			kwic: KWIC{Left: 1, Right: 1, Sort: SortRight},
+			want: []line{
+				{":", "US", "."},
+				{"the", "US", "lied"},
+				{"the", "US", "signed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, lines(frames.Concordance(tt.kwic))); diff != "" {
+				t.Errorf("Frames.Concordance() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("export", func(t *testing.T) {
+		t.Parallel()
+
+		concordance := frames.Concordance(KWIC{Left: 1, Right: 1})
+
+		var text bytes.Buffer
+		if err := concordance.WriteText(&text); err != nil {
+			t.Fatalf("Concordance.WriteText() = %s, want nil", err)
+		}
+		want := "the  US  signed\nthe  US  lied\n  :  US  .\n"
+		if got := text.String(); got != want {
+			t.Errorf("Concordance.WriteText() = %q, want %q", got, want)
+		}
+
+		var csv bytes.Buffer
+		if err := concordance.WriteCSV(&csv); err != nil {
+			t.Fatalf("Concordance.WriteCSV() = %s, want nil", err)
+		}
+		if got := strings.Split(csv.String(), "\n")[1]; got != "0,0,US,US,the,US,signed" {
+			t.Errorf("Concordance.WriteCSV() line = %q, want %q", got, "0,0,US,US,the,US,signed")
+		}
+
+		var html bytes.Buffer
+		if err := concordance.WriteHTML(&html); err != nil {
+			t.Fatalf("Concordance.WriteHTML() = %s, want nil", err)
+		}
+		if !strings.Contains(html.String(), `<td class="keyword"><mark>US</mark></td>`) {
+			t.Errorf("Concordance.WriteHTML() = %s, want marked keyword", html.String())
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package entitydebs
+
+import (
+	"iter"
+	"maps"
+	"slices"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+// Mention is an entity mention within a frame.
+type Mention struct {
+	// Frame is the index of the frame.
+	Frame int
+	// Sentence is the index of the sentence within the frame.
+	Sentence int
+	// Start and End are the token index range within the frame. End is
+	// exclusive.
+	Start, End int
+	// BeginOffset and EndOffset are the character span within the text, as
+	// reported by the tokenizer. EndOffset is exclusive.
+	BeginOffset, EndOffset int
+	// Entity is the entity ID.
+	Entity string
+	// Alias is the matched alias or pattern.
+	Alias string
+	// Coref is true if the mention is a coreference, see [Frames.Resolve].
+	Coref bool
+	// Tokens contains the mention tokens.
+	Tokens []*tokenize.Token
+	// Text is the text of the sentence.
+	Text string
+}
+
+// Mentions returns all entity mentions of all frames, ordered by frame and
+// token offset.
+func (f Frames) Mentions() iter.Seq[Mention] {
+	return func(yield func(Mention) bool) {
+		for i, frame := range f.frames {
+			if len(frame.entities) == 0 {
+				continue
+			}
+
+			indices := frame.sentenceIndices()
+			for _, offset := range slices.Sorted(maps.Keys(frame.entities)) {
+				var (
+					tokens  = frame.entities[offset]
+					match   = frame.matches[offset]
+					mention = Mention{
+						Frame:       i,
+						Start:       offset,
+						End:         offset + len(tokens),
+						BeginOffset: -1,
+						EndOffset:   -1,
+						Entity:      match.id,
+						Alias:       match.alias,
+						Coref:       match.coref,
+						Tokens:      tokens,
+					}
+				)
+				if offset < len(indices) {
+					mention.Sentence = indices[offset]
+				}
+				if mention.Sentence < len(frame.sentences) {
+					if text := frame.sentences[mention.Sentence].Text; text != nil {
+						mention.Text = text.Content
+					}
+				}
+				if len(tokens) > 0 && tokens[0].Text != nil && tokens[len(tokens)-1].Text != nil {
+					mention.BeginOffset = tokenBegin(tokens[0])
+					mention.EndOffset = tokenEnd(tokens[len(tokens)-1])
+				}
+
+				if !yield(mention) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package entitydebs
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestFramesMentions(t *testing.T) {
+	t.Parallel()
+
+	src := NewMultiSource([]Entity{
+		{ID: "us", Aliases: []string{"US", "United States"}},
+	}, []string{"One sentence. The US acts here.", "Nothing.", "United States and US."})
+	frames, err := src.Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	want := []Mention{
+		{
+			Frame:       0,
+			Sentence:    1,
+			Start:       4,
+			End:         5,
+			BeginOffset: 18,
+			EndOffset:   20,
+			Entity:      "us",
+			Alias:       "US",
+			Text:        "The US acts here.",
+		},
+		{
+			Frame:       2,
+			Start:       0,
+			End:         2,
+			BeginOffset: 0,
+			EndOffset:   13,
+			Entity:      "us",
+			Alias:       "United States",
+			Text:        "United States and US.",
+		},
+		{
+			Frame:       2,
+			Start:       3,
+			End:         4,
+			BeginOffset: 18,
+			EndOffset:   20,
+			Entity:      "us",
+			Alias:       "US",
+			Text:        "United States and US.",
+		},
+	}
+	got := slices.Collect(frames.Mentions())
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(Mention{}, "Tokens")); diff != "" {
+		t.Errorf("Frames.Mentions() mismatch (-want +got):\n%s", diff)
+	}
+	if got := got[1].Tokens; len(got) != 2 || got[0] != frames.frames[2].tokens[0] {
+		t.Errorf("Frames.Mentions()[1].Tokens = %v, want the first two tokens of frame 2", got)
+	}
+}
@@ -0,0 +1,67 @@
+package entitydebs
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+// Merge returns a new [Frames] of all frames, in order. See [Frames.Append].
+func Merge(frames ...Frames) (Frames, error) {
+	var merged Frames
+	if err := merged.Append(frames...); err != nil {
+		return Frames{}, err
+	}
+
+	return merged, nil
+}
+
+// Append appends the frames of others to f, e.g., of separate runs. Entity
+// alias maps are reconciled: an alias keeps its first tokens, and it is an
+// error if an alias belongs to different entities. Then f is left unchanged.
+//
+// Frames keep the text index of their run. The forest is computed anew.
+func (f *Frames) Append(others ...Frames) error {
+	// Reconcile entity IDs first, so f is unchanged on conflicts.
+	ids := maps.Clone(f.ids)
+	if ids == nil {
+		ids = make(map[string]string)
+	}
+	for _, other := range others {
+		for alias, id := range other.ids {
+			if existing, ok := ids[alias]; ok && existing != id {
+				return fmt.Errorf("entitydebs: alias %q: conflicting entities %q and %q", alias, existing, id)
+			}
+			ids[alias] = id
+		}
+	}
+
+	entities := maps.Clone(f.entities)
+	if entities == nil {
+		entities = make(map[string][]tokenize.Token)
+	}
+	// Never append into a backing array shared with other frames.
+	f.frames = slices.Clip(f.frames)
+	for _, other := range others {
+		for alias, tokens := range other.entities {
+			if _, ok := entities[alias]; !ok {
+				entities[alias] = tokens
+			}
+		}
+		// Frames don't share mutable state with other, e.g., for
+		// [Frames.Resolve].
+		for _, frame := range other.frames {
+			frame.entities = maps.Clone(frame.entities)
+			frame.matches = maps.Clone(frame.matches)
+			f.frames = append(f.frames, frame)
+		}
+	}
+	f.entities = entities
+	f.ids = ids
+	// Invalidate the cached forest.
+	f.deps = deps{}
+
+	return nil
+}
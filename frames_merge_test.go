@@ -0,0 +1,58 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestFramesAppend(t *testing.T) {
+	t.Parallel()
+
+	example := newExampleFrames(t)
+	var (
+		denver = Frames{
+			frames:   example.frames[:1],
+			entities: map[string][]tokenize.Token{"Denver": nil},
+			ids:      map[string]string{"Denver": "denver"},
+		}
+		houston = Frames{
+			frames:   example.frames[1:],
+			entities: example.entities,
+			ids:      example.ids,
+		}
+	)
+
+	// Cache the forest before appending.
+	if got := len(denver.Forest().entities); got != 2 {
+		t.Fatalf("len(Frames.Forest().entities) = %d, want 2", got)
+	}
+	if err := denver.Append(houston); err != nil {
+		t.Fatalf("Frames.Append() = %s, want nil", err)
+	}
+	if got := len(denver.frames); got != 2 {
+		t.Errorf("len(Frames.Append().frames) = %d, want 2", got)
+	}
+	if got := len(denver.Forest().entities); got != 3 {
+		t.Errorf("len(Frames.Forest().entities) = %d, want 3", got)
+	}
+	want := map[string]string{"Denver": "denver", "Houston": "houston"}
+	if diff := cmp.Diff(want, denver.ids); diff != "" {
+		t.Errorf("Frames.Append().ids mismatch (-want +got):\n%s", diff)
+	}
+
+	t.Run("conflicting entities", func(t *testing.T) {
+		t.Parallel()
+
+		other := Frames{ids: map[string]string{"Houston": "texas"}}
+		merged, err := Merge(houston, other)
+		want := `entitydebs: alias "Houston": conflicting entities "houston" and "texas"`
+		if err == nil || err.Error() != want {
+			t.Fatalf("Merge() = _, %v, want %s", err, want)
+		}
+		if len(merged.frames) != 0 {
+			t.Errorf("len(Merge().frames) = %d, want 0", len(merged.frames))
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package entitydebs
+
+import (
+	"math"
+	"slices"
+)
+
+type (
+	// Sentiments configures an entity sentiment aggregation, see
+	// [Frames.Sentiments].
+	Sentiments struct {
+		// Entity restricts mentions to the entity with the ID. Empty
+		// considers all entities.
+		Entity string
+		// GroupBy is the metadata key frames are grouped by, see
+		// [WithMetadata]. Empty doesn't group.
+		GroupBy string
+		// Threshold is the maximum absolute score of a neutral sentence. Zero
+		// considers only zero scores neutral.
+		Threshold float64
+		// Bins is the number of equal-width bins of the distribution over
+		// [-1, 1]. Zero defaults to 10.
+		Bins int
+	}
+
+	// SentimentSummary summarizes the sentiment of sentences that contain
+	// entity mentions.
+	SentimentSummary struct {
+		// Sentences is the number of sentences with a sentiment.
+		Sentences int
+		// Fallback is the number of sentences without a sentiment of their
+		// own, which use the document sentiment of their frame instead.
+		Fallback int
+		// Mean and Median are the mean and median scores.
+		Mean, Median float64
+		// Weighted is the mean score weighted by magnitude. It's zero if all
+		// magnitudes are zero.
+		Weighted float64
+		// Distribution counts the scores per bin, from negative to positive.
+		Distribution []int
+		// Positive, Neutral and Negative count the sentences by score, see
+		// [Sentiments.Threshold].
+		Positive, Neutral, Negative int
+	}
+)
+
+// Sentiments returns the sentiment summaries of sentences that contain entity
+// mentions, keyed by the metadata value of [Sentiments.GroupBy]. Without
+// grouping, the only key is empty. A sentence is counted once, even if it
+// contains several mentions. Sentences without a sentiment fall back to the
+// document sentiment of their frame, see [SentimentSummary.Fallback].
+// Sentences without both are skipped.
+func (f Frames) Sentiments(sentiments Sentiments) map[string]SentimentSummary {
+	bins := sentiments.Bins
+	if bins <= 0 {
+		bins = 10
+	}
+
+	type sentence struct{ frame, sentence int }
+	var (
+		seen       = make(map[sentence]struct{})
+		scores     = make(map[string][]float64)
+		magnitudes = make(map[string][]float64)
+		fallbacks  = make(map[string]int)
+	)
+	for mention := range f.Mentions() {
+		if sentiments.Entity != "" && mention.Entity != sentiments.Entity {
+			continue
+		}
+		key := sentence{mention.Frame, mention.Sentence}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		var (
+			frame     = f.frames[mention.Frame]
+			sentiment = frame.sentiment
+			fallback  = true
+		)
+		if mention.Sentence < len(frame.sentences) && frame.sentences[mention.Sentence].Sentiment != nil {
+			sentiment = frame.sentences[mention.Sentence].Sentiment
+			fallback = false
+		}
+		if sentiment == nil {
+			continue
+		}
+
+		var group string
+		if sentiments.GroupBy != "" {
+			group = frame.metadata[sentiments.GroupBy]
+		}
+		if fallback {
+			fallbacks[group]++
+		}
+		scores[group] = append(scores[group], float64(sentiment.Score))
+		magnitudes[group] = append(magnitudes[group], float64(sentiment.Magnitude))
+	}
+
+	summaries := make(map[string]SentimentSummary, len(scores))
+	for group := range scores {
+		summary := summarize(scores[group], magnitudes[group], sentiments.Threshold, bins)
+		summary.Fallback = fallbacks[group]
+		summaries[group] = summary
+	}
+
+	return summaries
+}
+
+// summarize returns the sentiment summary of scores and their magnitudes.
+func summarize(scores, magnitudes []float64, threshold float64, bins int) SentimentSummary {
+	summary := SentimentSummary{
+		Sentences:    len(scores),
+		Distribution: make([]int, bins),
+	}
+
+	var sum, weighted, magnitude float64
+	for i, score := range scores {
+		sum += score
+		weighted += score * magnitudes[i]
+		magnitude += magnitudes[i]
+
+		switch {
+		case score > threshold:
+			summary.Positive++
+		case score < -threshold:
+			summary.Negative++
+		default:
+			summary.Neutral++
+		}
+
+		bin := int(math.Floor((score + 1) / 2 * float64(bins)))
+		summary.Distribution[min(max(bin, 0), bins-1)]++
+	}
+	summary.Mean = sum / float64(len(scores))
+	if magnitude > 0 {
+		summary.Weighted = weighted / magnitude
+	}
+
+	sorted := slices.Sorted(slices.Values(scores))
+	if n := len(sorted); n%2 == 1 {
+		summary.Median = sorted[n/2]
+	} else {
+		summary.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	return summary
+}
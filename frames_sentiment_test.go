@@ -0,0 +1,144 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ndabAP/entitydebs/testhelper"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestFramesSentiments(t *testing.T) {
+	t.Parallel()
+
+	var (
+		token = func(content string, offset int32) *tokenize.Token {
+			return &tokenize.Token{Text: &tokenize.TextSpan{Content: content, BeginOffset: offset}}
+		}
+
+		// US wins. US and US lose. US waits.
+		tokens1 = []*tokenize.Token{
+			token("US", 0), token("wins", 3), token(".", 7),
+			token("US", 9), token("and", 12), token("US", 16), token("lose", 19), token(".", 23),
+			token("US", 25), token("waits", 28), token(".", 33),
+		}
+		// UK and US rest.
+		tokens2 = []*tokenize.Token{
+			token("UK", 0), token("and", 3), token("US", 7), token("rest", 10), token(".", 14),
+		}
+		// US is.
+		tokens3 = []*tokenize.Token{
+			token("US", 0), token("is", 3), token(".", 5),
+		}
+
+		us = match{id: "us", alias: "US"}
+	)
+
+	tests := []struct {
+		name       string
+		sentiments Sentiments
+		want       map[string]SentimentSummary
+	}{
+		{
+			name:       "all",
+			sentiments: Sentiments{Threshold: 0.05, Bins: 4},
+			want: map[string]SentimentSummary{
+				"": {
+					Sentences:    4,
+					Fallback:     1,
+					Mean:         0.1375,
+					Median:       0.05,
+					Weighted:     0.6 / 2.8,
+					Distribution: []int{0, 1, 2, 1},
+					Positive:     2,
+					Neutral:      1,
+					Negative:     1,
+				},
+			},
+		},
+		{
+			name:       "entity",
+			sentiments: Sentiments{Entity: "uk", GroupBy: "party"},
+			want: map[string]SentimentSummary{
+				"rep": {
+					Sentences:    1,
+					Distribution: []int{0, 0, 0, 0, 0, 1, 0, 0, 0, 0},
+					Neutral:      1,
+				},
+			},
+		},
+		{
+			name:       "group",
+			sentiments: Sentiments{GroupBy: "party"},
+			want: map[string]SentimentSummary{
+				"dem": {
+					Sentences:    3,
+					Fallback:     1,
+					Mean:         0.55 / 3,
+					Median:       0.1,
+					Weighted:     0.6 / 2.2,
+					Distribution: []int{0, 0, 0, 1, 0, 1, 0, 0, 0, 1},
+					Positive:     2,
+					Negative:     1,
+				},
+				"rep": {
+					Sentences:    1,
+					Distribution: []int{0, 0, 0, 0, 0, 1, 0, 0, 0, 0},
+					Neutral:      1,
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			frames := Frames{
+				frames: []frame{
+					{
+						metadata: map[string]string{"party": "dem"},
+						sentences: []*tokenize.Sentence{
+							testhelper.NewSentence(t, "US wins.", 0, &tokenize.Sentiment{Score: 0.8, Magnitude: 0.8}),
+							testhelper.NewSentence(t, "US and US lose.", 9, &tokenize.Sentiment{Score: -0.35, Magnitude: 0.4}),
+							testhelper.NewSentence(t, "US waits.", 25, nil),
+						},
+						sentiment: &tokenize.Sentiment{Score: 0.1, Magnitude: 1},
+						tokens:    tokens1,
+						entities: map[int][]*tokenize.Token{
+							0: {tokens1[0]},
+							3: {tokens1[3]},
+							5: {tokens1[5]},
+							8: {tokens1[8]},
+						},
+						matches: map[int]match{0: us, 3: us, 5: us, 8: us},
+					},
+					{
+						metadata: map[string]string{"party": "rep"},
+						sentences: []*tokenize.Sentence{
+							testhelper.NewSentence(t, "UK and US rest.", 0, &tokenize.Sentiment{Score: 0, Magnitude: 0.6}),
+						},
+						tokens: tokens2,
+						entities: map[int][]*tokenize.Token{
+							0: {tokens2[0]},
+							2: {tokens2[2]},
+						},
+						matches: map[int]match{0: {id: "uk", alias: "UK"}, 2: us},
+					},
+					{
+						sentences: []*tokenize.Sentence{
+							testhelper.NewSentence(t, "US is.", 0, nil),
+						},
+						tokens:   tokens3,
+						entities: map[int][]*tokenize.Token{0: {tokens3[0]}},
+						matches:  map[int]match{0: us},
+					},
+				},
+			}
+			got := frames.Sentiments(test.sentiments)
+			if diff := cmp.Diff(test.want, got, cmpopts.EquateApprox(0, 1e-6)); diff != "" {
+				t.Errorf("Frames.Sentiments() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package entitydebs
+
+import (
+	"github.com/ndabAP/entitydebs/tokenize"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Match is the entity matching mode. Entity tokens and text tokens are
+// compared under the same mode. Modes can be combined, e.g.,
+// MatchFold|MatchNFKC.
+type Match int
+
+const (
+	// MatchExact compares tokens byte-for-byte. This is the default.
+	MatchExact Match = 0
+
+	// MatchFold compares tokens case-insensitively using Unicode case folding,
+	// e.g., "AMERICA" matches "America".
+	MatchFold Match = 1 << iota
+	// MatchNFKC compares tokens in Unicode Normalization Form KC, e.g., "ﬀ"
+	// matches "ff".
+	MatchNFKC
+	// MatchLemma compares token lemmas, e.g., "Americans" matches "American".
+	// Tokens without lemma are compared by their text.
+	MatchLemma
+)
+
+// WithMatch sets the entity matching mode.
+func WithMatch(mode Match) Option {
+	return func(source *source) {
+		source.match = mode
+	}
+}
+
+// key returns the token text as compared under the matching mode.
+func (mode Match) key(token *tokenize.Token) string {
+	s := token.Text.Content
+	if mode&MatchLemma != 0 && token.Lemma != "" {
+		s = token.Lemma
+	}
+
+	return mode.normalize(s)
+}
+
+// normalize returns s as compared under the matching mode. Unlike key, it
+// doesn't consider lemmas.
+func (mode Match) normalize(s string) string {
+	if mode&MatchNFKC != 0 {
+		s = norm.NFKC.String(s)
+	}
+	if mode&MatchFold != 0 {
+		s = cases.Fold().String(s)
+	}
+
+	return s
+}
@@ -0,0 +1,104 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestMatch_key(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		mode  Match
+		token *tokenize.Token
+		want  string
+	}{
+		{
+			name:  "exact",
+			mode:  MatchExact,
+			token: &tokenize.Token{Text: &tokenize.TextSpan{Content: "AMERICA"}},
+			want:  "AMERICA",
+		},
+		{
+			name:  "fold",
+			mode:  MatchFold,
+			token: &tokenize.Token{Text: &tokenize.TextSpan{Content: "AMERICA"}},
+			want:  "america",
+		},
+		{
+			name:  "NFKC",
+			mode:  MatchNFKC,
+			token: &tokenize.Token{Text: &tokenize.TextSpan{Content: "ＵＳ"}},
+			want:  "US",
+		},
+		{
+			name:  "NFKC and fold",
+			mode:  MatchNFKC | MatchFold,
+			token: &tokenize.Token{Text: &tokenize.TextSpan{Content: "ＵＳ"}},
+			want:  "us",
+		},
+		{
+			name:  "lemma",
+			mode:  MatchLemma,
+			token: &tokenize.Token{Text: &tokenize.TextSpan{Content: "Americans"}, Lemma: "American"},
+			want:  "American",
+		},
+		{
+			name:  "lemma missing",
+			mode:  MatchLemma,
+			token: &tokenize.Token{Text: &tokenize.TextSpan{Content: "Americans"}},
+			want:  "Americans",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.mode.key(tt.token); got != tt.want {
+				t.Errorf("Match.key() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sourceFramesWithMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		mode  Match
+		texts []string
+		want  []int
+	}{
+		{
+			name:  "exact",
+			mode:  MatchExact,
+			texts: []string{"God bless america.", "GOD BLESS AMERICA", "God bless America."},
+			want:  []int{0, 0, 1},
+		},
+		{
+			name:  "fold",
+			mode:  MatchFold,
+			texts: []string{"God bless america.", "GOD BLESS AMERICA", "God bless America."},
+			want:  []int{1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src := NewSource([]string{"America"}, tt.texts, WithMatch(tt.mode))
+			frames, err := src.Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax)
+			if err != nil {
+				t.Fatalf("source.Frames() = _, %s, want nil", err)
+			}
+			for i, frame := range frames.frames {
+				if got := len(frame.entities); got != tt.want[i] {
+					t.Errorf("len(source.Frames().frames[%d].entities) = %d, want %d", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
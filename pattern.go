@@ -0,0 +1,410 @@
+package entitydebs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+type (
+	// Pattern is a sequence of token constraints that defines an entity as a
+	// category of phrases, e.g., "Senator [PROPER NOUN]+".
+	Pattern []PatternToken
+
+	// PatternToken constrains a single pattern token. Unset constraints are not
+	// checked, a zero PatternToken is a wildcard that matches any token.
+	PatternToken struct {
+		// Text is compared with the token text under the matching mode.
+		Text string
+		// Lemma is compared with the token lemma under the matching mode.
+		Lemma string
+		// Regexp is matched against the token text.
+		Regexp *regexp.Regexp
+		// Tags contains the allowed part of speech tags.
+		Tags []tokenize.PartOfSpeechTag
+		// Proper requires a proper noun.
+		Proper bool
+		// Quantifier sets how often the pattern token may repeat.
+		Quantifier Quantifier
+	}
+
+	// Quantifier sets how often a pattern token may repeat.
+	Quantifier int
+)
+
+const (
+	// QuantifierOne matches exactly one token.
+	QuantifierOne Quantifier = iota
+	// QuantifierOptional matches zero or one token, written as "?".
+	QuantifierOptional
+	// QuantifierOneOrMore matches one or more tokens, written as "+".
+	QuantifierOneOrMore
+	// QuantifierZeroOrMore matches zero or more tokens, written as "*".
+	QuantifierZeroOrMore
+)
+
+var (
+	// tags maps pattern tag names to part of speech tags.
+	tags = map[string]tokenize.PartOfSpeechTag{
+		"ADJ":   tokenize.PartOfSpeechTagAdj,
+		"ADP":   tokenize.PartOfSpeechTagAdp,
+		"ADV":   tokenize.PartOfSpeechTagAdv,
+		"CONJ":  tokenize.PartOfSpeechTagConj,
+		"DET":   tokenize.PartOfSpeechTagDet,
+		"NOUN":  tokenize.PartOfSpeechTagNoun,
+		"NUM":   tokenize.PartOfSpeechTagNum,
+		"PRON":  tokenize.PartOfSpeechTagPron,
+		"PRT":   tokenize.PartOfSpeechTagPrt,
+		"PUNCT": tokenize.PartOfSpeechTagPunct,
+		"VERB":  tokenize.PartOfSpeechTagVerb,
+		"X":     tokenize.PartOfSpeechTagX,
+		"AFFIX": tokenize.PartOfSpeechTagAffix,
+	}
+
+	// quantifiers maps quantifier suffixes to quantifiers.
+	quantifiers = map[byte]Quantifier{
+		'?': QuantifierOptional,
+		'+': QuantifierOneOrMore,
+		'*': QuantifierZeroOrMore,
+	}
+
+	errPatternEmpty = errors.New("entitydebs: empty pattern")
+)
+
+// ParsePattern parses a pattern. Pattern tokens are separated by white space
+// and are either literal texts or bracketed constraints. Constraints are
+// separated by white space and consist of:
+//
+//   - Part of speech tags, e.g., NOUN, VERB or ADJ
+//   - PROPER, to require a proper noun
+//   - text=<text>, to require a text
+//   - lemma=<lemma>, to require a lemma
+//   - /<regexp>/, to require the token text to fully match the expression
+//   - *, to match any token
+//
+// Every pattern token can be followed by a quantifier, "?" (optional), "+" (one
+// or more) or "*" (zero or more). For example:
+//
+//	Senator [PROPER NOUN]+
+//	the [ADJ]? government of Germany
+func ParsePattern(s string) (Pattern, error) {
+	pattern := make(Pattern, 0)
+
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		var (
+			token PatternToken
+			err   error
+			n     int
+		)
+		if s[0] == '[' {
+			var fields []string
+			fields, n = splitConstraints(s[1:])
+			if n == -1 {
+				return nil, fmt.Errorf("entitydebs: unterminated pattern token %q", s)
+			}
+			token, err = parseConstraints(fields)
+			if err != nil {
+				return nil, err
+			}
+			// Skip both brackets.
+			n += 2
+			if n < len(s) {
+				if q, ok := quantifiers[s[n]]; ok {
+					token.Quantifier = q
+					n++
+				}
+			}
+		} else {
+			n = strings.IndexFunc(s, isSpace)
+			if n == -1 {
+				n = len(s)
+			}
+			token.Text = s[:n]
+			// A single character is always literal.
+			if q, ok := quantifiers[token.Text[len(token.Text)-1]]; ok && len(token.Text) > 1 {
+				token.Text = token.Text[:len(token.Text)-1]
+				token.Quantifier = q
+			}
+		}
+
+		pattern = append(pattern, token)
+		s = s[n:]
+	}
+	if len(pattern) == 0 {
+		return nil, errPatternEmpty
+	}
+
+	return pattern, nil
+}
+
+// MustParsePattern is like [ParsePattern] but panics if the pattern can't be
+// parsed.
+func MustParsePattern(s string) Pattern {
+	pattern, err := ParsePattern(s)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return pattern
+}
+
+// splitConstraints splits the bracketed constraints at the beginning of s,
+// which lacks the opening bracket, into fields. Expressions may contain white
+// space and brackets, and end with a slash that is followed by white space or
+// the closing bracket. splitConstraints returns the fields and the index of the
+// closing bracket, or -1 if there is none.
+func splitConstraints(s string) ([]string, int) {
+	var (
+		fields = make([]string, 0)
+		begin  = -1
+		expr   bool
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case expr:
+			switch {
+			case c == '\\':
+				// Skip escaped character.
+				i++
+			case c == '/' && (i+1 == len(s) || s[i+1] == ']' || isSpace(rune(s[i+1]))):
+				expr = false
+			}
+
+		case c == ']':
+			if begin >= 0 {
+				fields = append(fields, s[begin:i])
+			}
+			return fields, i
+
+		case isSpace(rune(c)):
+			if begin >= 0 {
+				fields = append(fields, s[begin:i])
+				begin = -1
+			}
+
+		case begin < 0:
+			begin = i
+			expr = c == '/'
+		}
+	}
+
+	return nil, -1
+}
+
+// parseConstraints parses bracketed pattern token constraints.
+func parseConstraints(fields []string) (PatternToken, error) {
+	var token PatternToken
+	for _, field := range fields {
+		switch {
+		case field == "*":
+			// Wildcard
+
+		case field == "PROPER":
+			token.Proper = true
+
+		case strings.HasPrefix(field, "text="):
+			token.Text = strings.TrimPrefix(field, "text=")
+
+		case strings.HasPrefix(field, "lemma="):
+			token.Lemma = strings.TrimPrefix(field, "lemma=")
+
+		case len(field) > 1 && field[0] == '/' && field[len(field)-1] == '/':
+			re, err := regexp.Compile("^(?:" + field[1:len(field)-1] + ")$")
+			if err != nil {
+				return token, fmt.Errorf("entitydebs: invalid pattern expression: %w", err)
+			}
+			token.Regexp = re
+
+		default:
+			tag, ok := tags[field]
+			if !ok {
+				return token, fmt.Errorf("entitydebs: unknown pattern constraint %q", field)
+			}
+			token.Tags = append(token.Tags, tag)
+		}
+	}
+
+	return token, nil
+}
+
+// String returns the pattern in its parsable form.
+func (pattern Pattern) String() string {
+	tokens := make([]string, 0, len(pattern))
+	for _, token := range pattern {
+		tokens = append(tokens, token.String())
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// String returns the pattern token in its parsable form.
+func (token PatternToken) String() string {
+	var sb strings.Builder
+
+	constraints := make([]string, 0)
+	if token.Text != "" {
+		constraints = append(constraints, "text="+token.Text)
+	}
+	if token.Proper {
+		constraints = append(constraints, "PROPER")
+	}
+	for _, tag := range token.Tags {
+		for name, t := range tags {
+			if t == tag {
+				constraints = append(constraints, name)
+			}
+		}
+	}
+	if token.Lemma != "" {
+		constraints = append(constraints, "lemma="+token.Lemma)
+	}
+	if token.Regexp != nil {
+		expr := token.Regexp.String()
+		expr = strings.TrimSuffix(strings.TrimPrefix(expr, "^(?:"), ")$")
+		constraints = append(constraints, "/"+expr+"/")
+	}
+
+	switch {
+	case token.Text != "" && len(constraints) == 1:
+		sb.WriteString(token.Text)
+	case len(constraints) == 0:
+		sb.WriteString("[*]")
+	default:
+		sb.WriteString("[" + strings.Join(constraints, " ") + "]")
+	}
+
+	for q, quantifier := range quantifiers {
+		if quantifier == token.Quantifier {
+			sb.WriteByte(q)
+		}
+	}
+
+	return sb.String()
+}
+
+// match returns the number of tokens of the longest match of pattern at the
+// beginning of tokens. If the pattern doesn't match, match returns -1.
+//
+// A state is a pattern index i and a token index j, and is reached at most
+// once, so matching takes O(len(pattern)·len(tokens)) steps. Repeated pattern
+// tokens that already matched once have their own states, since they may be
+// skipped. Matching stops at the first token without states.
+func (pattern Pattern) match(tokens []*tokenize.Token, mode Match) int {
+	var (
+		states = len(pattern) + 1
+		buf    = make([]bool, 4*states)
+		// reached[i] is the state before pattern token i at token j,
+		// repeated[i] the same state after one repetition. next and
+		// nextRepeated are the states at token j+1.
+		reached, repeated  = buf[:states], buf[states : 2*states]
+		next, nextRepeated = buf[2*states : 3*states], buf[3*states:]
+		longest            = -1
+	)
+	reached[0] = true
+	for j := 0; j <= len(tokens); j++ {
+		// Transitions without consuming tokens only advance i, so every state
+		// at j is final once i reaches it.
+		active := false
+		for i := range states {
+			once := repeated[i]
+			if !reached[i] && !once {
+				continue
+			}
+			active = true
+			if i == len(pattern) {
+				longest = j
+				continue
+			}
+
+			var (
+				token   = pattern[i]
+				matches = j < len(tokens) && token.match(tokens[j], mode)
+			)
+			switch token.Quantifier {
+			case QuantifierOne:
+				if matches {
+					next[i+1] = true
+				}
+
+			case QuantifierOptional:
+				reached[i+1] = true
+				if matches {
+					next[i+1] = true
+				}
+
+			case QuantifierOneOrMore, QuantifierZeroOrMore:
+				if once || token.Quantifier == QuantifierZeroOrMore {
+					reached[i+1] = true
+				}
+				if matches {
+					nextRepeated[i] = true
+				}
+			}
+		}
+		if !active {
+			break
+		}
+
+		reached, next = next, reached
+		repeated, nextRepeated = nextRepeated, repeated
+		clear(next)
+		clear(nextRepeated)
+	}
+
+	// Empty matches are no matches.
+	if longest == 0 {
+		return -1
+	}
+
+	return longest
+}
+
+// maxLen returns the maximum number of tokens a match of pattern consists of,
+// or -1 if repetitions make it unbounded.
+func (pattern Pattern) maxLen() int {
+	n := 0
+	for _, token := range pattern {
+		switch token.Quantifier {
+		case QuantifierOneOrMore, QuantifierZeroOrMore:
+			return -1
+		}
+		n++
+	}
+
+	return n
+}
+
+// match reports whether the token satisfies all constraints under the
+// matching mode.
+func (token PatternToken) match(t *tokenize.Token, mode Match) bool {
+	if t.Text == nil {
+		return false
+	}
+	if token.Text != "" && mode.normalize(token.Text) != mode.normalize(t.Text.Content) {
+		return false
+	}
+	if token.Lemma != "" && mode.normalize(token.Lemma) != mode.normalize(t.Lemma) {
+		return false
+	}
+	if token.Regexp != nil && !token.Regexp.MatchString(t.Text.Content) {
+		return false
+	}
+	if len(token.Tags) > 0 && (t.PartOfSpeech == nil || !slices.Contains(token.Tags, t.PartOfSpeech.Tag)) {
+		return false
+	}
+	if token.Proper && (t.PartOfSpeech == nil || t.PartOfSpeech.Proper != tokenize.PartOfSpeechIsProper) {
+		return false
+	}
+
+	return true
+}
+
+// isSpace reports whether r is a pattern token separator.
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n'
+}
@@ -0,0 +1,165 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/testhelper"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestParsePattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		want    string
+		err     bool
+	}{
+		{pattern: "Senator [PROPER NOUN]+", want: "Senator [PROPER NOUN]+"},
+		{pattern: " the [ADJ]?  government of Germany ", want: "the [ADJ]? government of Germany"},
+		{pattern: "United? States", want: "United? States"},
+		{pattern: "[*]* [lemma=run VERB]", want: "[*]* [VERB lemma=run]"},
+		{pattern: "[text=U.S. /U.*/]", want: "[text=U.S. /U.*/]"},
+		{pattern: "[/[A-Z]+/]+ Inc", want: "[/[A-Z]+/]+ Inc"},
+		{pattern: "[/New York|L\\/A/ NOUN]", want: "[NOUN /New York|L\\/A/]"},
+		{pattern: "?", want: "?"},
+		{pattern: "", err: true},
+		{pattern: "[NOUN", err: true},
+		{pattern: "[NOUNS]", err: true},
+		{pattern: "[/(/]", err: true},
+		{pattern: "[/[A-Z]/", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			t.Parallel()
+
+			pattern, err := ParsePattern(tt.pattern)
+			if (err != nil) != tt.err {
+				t.Fatalf("ParsePattern(%q) = _, %v, want error %t", tt.pattern, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got := pattern.String(); got != tt.want {
+				t.Errorf("ParsePattern(%q).String() = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPattern_match(t *testing.T) {
+	t.Parallel()
+
+	// I prefer the morning flight through Denver.
+	tokens := testhelper.NewExampleTokens1(t, 0, 0)
+
+	tests := []struct {
+		pattern string
+		mode    Match
+		offset  int
+		want    int
+	}{
+		{pattern: "[DET] [NOUN]+", offset: 2, want: 3},
+		{pattern: "[DET] [NOUN]+ [ADP]", offset: 2, want: 4},
+		{pattern: "through [PROPER NOUN]", offset: 5, want: 2},
+		{pattern: "the [ADJ]? morning", offset: 2, want: 2},
+		{pattern: "[lemma=prefer]", offset: 1, want: 1},
+		{pattern: "[/fl.*/] [*]*", offset: 4, want: 4},
+		{pattern: "THE", offset: 2, want: -1},
+		{pattern: "THE", mode: MatchFold, offset: 2, want: 1},
+		{pattern: "[PROPER]", offset: 2, want: -1},
+		{pattern: "[ADJ]?", offset: 2, want: -1},
+		{pattern: "Denver . [*]", offset: 6, want: -1},
+		{pattern: "[*]+ [*]+ Denver", offset: 0, want: 7},
+		{pattern: "[NOUN]+ [NOUN]", offset: 3, want: 2},
+		{pattern: "[NOUN]* flight", offset: 3, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			t.Parallel()
+
+			pattern := MustParsePattern(tt.pattern)
+			if got := pattern.match(tokens[tt.offset:], tt.mode); got != tt.want {
+				t.Errorf("Pattern(%q).match() = %d, want %d", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPattern_matchBacktracking(t *testing.T) {
+	t.Parallel()
+
+	tokens := make([]*tokenize.Token, 400)
+	for i := range tokens {
+		tokens[i] = &tokenize.Token{Text: &tokenize.TextSpan{Content: "word"}}
+	}
+
+	// Backtracking takes cubic time per start position for this pattern.
+	pattern := MustParsePattern("[*]+ [*]+ [*]+ nomatch")
+	for i := range tokens {
+		if got := pattern.match(tokens[i:], MatchExact); got != -1 {
+			t.Fatalf("Pattern(%q).match() = %d, want -1", pattern, got)
+		}
+	}
+}
+
+func TestPattern_matchStops(t *testing.T) {
+	t.Parallel()
+
+	tokens := make([]*tokenize.Token, 20000)
+	for i := range tokens {
+		tokens[i] = &tokenize.Token{Text: &tokenize.TextSpan{Content: "word"}}
+	}
+
+	// Matching stops after the first token, so a sentence is scanned in
+	// linear time.
+	pattern := MustParsePattern("Senator [*]")
+	for i := range tokens {
+		if got := pattern.match(tokens[i:], MatchExact); got != -1 {
+			t.Fatalf("Pattern(%q).match() = %d, want -1", pattern, got)
+		}
+	}
+}
+
+func Test_sourceFramesPatterns(t *testing.T) {
+	t.Parallel()
+
+	src := NewMultiSource([]Entity{
+		{
+			ID:       "ny",
+			Aliases:  []string{"New"},
+			Patterns: []Pattern{MustParsePattern("New [/Y.*/]")},
+		},
+	}, []string{"Everything ripped apart in a New York minute."})
+	frames, err := src.Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	frame := frames.frames[0]
+	want := map[int]match{5: {id: "ny", alias: "New [/Y.*/]"}}
+	if diff := cmp.Diff(want, frame.matches, cmp.AllowUnexported(match{})); diff != "" {
+		t.Errorf("source.Frames().frames[0].matches mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(frame.tokens[5:7], frame.entities[5]); diff != "" {
+		t.Errorf("source.Frames().frames[0].entities mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_sourceFramesPatternsSentence(t *testing.T) {
+	t.Parallel()
+
+	src := NewMultiSource([]Entity{
+		{ID: "senator", Patterns: []Pattern{MustParsePattern("Senator [*]+")}},
+	}, []string{"Senator Smith spoke. Voters cheered."})
+	frames, err := src.Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	frame := frames.frames[0]
+	if diff := cmp.Diff(frame.tokens[0:4], frame.entities[0]); diff != "" {
+		t.Errorf("source.Frames().frames[0].entities mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,206 @@
+package entitydebs
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+type (
+	// Rule disambiguates matches of an alias or pattern to reject false
+	// matches, e.g., the pronoun "us" for the alias "US". Unset constraints are
+	// not checked.
+	Rule struct {
+		// Proper requires all matched tokens to be proper nouns.
+		Proper bool
+		// Tags contains the allowed part of speech tags of the matched tokens.
+		Tags []tokenize.PartOfSpeechTag
+		// CaseSensitive requires the matched tokens to equal the alias
+		// byte-for-byte, regardless of the matching mode.
+		CaseSensitive bool
+		// NotBefore contains negative context patterns. A match is rejected if
+		// a pattern matches the tokens immediately preceding it.
+		NotBefore []Pattern
+		// NotAfter contains negative context patterns. A match is rejected if
+		// a pattern matches the tokens immediately following it.
+		NotAfter []Pattern
+	}
+
+	// Reason is the reason a match was rejected by a [Rule].
+	Reason int
+
+	// Rejection is a match that was rejected by a [Rule].
+	Rejection struct {
+		// Frame is the index of the frame.
+		Frame int
+		// Offset is the index of the first rejected token within the frame.
+		Offset int
+		// Entity is the entity ID.
+		Entity string
+		// Alias is the rejected alias or pattern.
+		Alias string
+		// Tokens contains the rejected tokens.
+		Tokens []*tokenize.Token
+		// Reason is the reason of the rejection.
+		Reason Reason
+	}
+)
+
+const (
+	// ReasonProper rejects matches that aren't proper nouns.
+	ReasonProper Reason = iota + 1
+	// ReasonTag rejects matches with tokens of disallowed tags.
+	ReasonTag
+	// ReasonCase rejects matches whose case differs from the alias.
+	ReasonCase
+	// ReasonNotBefore rejects matches preceded by a negative context.
+	ReasonNotBefore
+	// ReasonNotAfter rejects matches followed by a negative context.
+	ReasonNotAfter
+)
+
+func (reason Reason) String() string {
+	switch reason {
+	case ReasonProper:
+		return "not a proper noun"
+	case ReasonTag:
+		return "tag not allowed"
+	case ReasonCase:
+		return "case mismatch"
+	case ReasonNotBefore:
+		return "negative context before"
+	case ReasonNotAfter:
+		return "negative context after"
+	default:
+		return "unknown"
+	}
+}
+
+// check checks the match of n tokens at offset i of the sentence tokens, so
+// negative contexts don't cross sentences. alias contains the alias tokens, or
+// is nil for patterns. It returns zero if the match is accepted.
+func (rule Rule) check(
+	tokens []*tokenize.Token,
+	i, n int,
+	alias []tokenize.Token,
+	pattern Pattern,
+	mode Match,
+) Reason {
+	matched := tokens[i : i+n]
+
+	if rule.Proper && slices.ContainsFunc(matched, func(token *tokenize.Token) bool {
+		return token.PartOfSpeech == nil || token.PartOfSpeech.Proper != tokenize.PartOfSpeechIsProper
+	}) {
+		return ReasonProper
+	}
+	if len(rule.Tags) > 0 && slices.ContainsFunc(matched, func(token *tokenize.Token) bool {
+		return token.PartOfSpeech == nil || !slices.Contains(rule.Tags, token.PartOfSpeech.Tag)
+	}) {
+		return ReasonTag
+	}
+	if rule.CaseSensitive {
+		switch {
+		case alias != nil:
+			for k, token := range matched {
+				if token.Text.Content != alias[k].Text.Content {
+					return ReasonCase
+				}
+			}
+		case pattern != nil:
+			if pattern.match(matched, MatchExact) != n {
+				return ReasonCase
+			}
+		}
+	}
+	for _, p := range rule.NotBefore {
+		// The pattern must end immediately before the match, so it can't
+		// start before its maximum length.
+		start := 0
+		if m := p.maxLen(); m >= 0 {
+			start = max(i-m, 0)
+		}
+		for j := i - 1; j >= start; j-- {
+			if p.match(tokens[j:i], mode) == i-j {
+				return ReasonNotBefore
+			}
+		}
+	}
+	for _, p := range rule.NotAfter {
+		if p.match(tokens[i+n:], mode) > 0 {
+			return ReasonNotAfter
+		}
+	}
+
+	return 0
+}
+
+// canonicalRules returns the rules of entity keyed by alias or by pattern in
+// its parsable form. Keys that aren't aliases are parsed as patterns, so
+// patterns may be written in any form.
+func canonicalRules(entity Entity) map[string]Rule {
+	if entity.Rules == nil {
+		return nil
+	}
+
+	rules := make(map[string]Rule, len(entity.Rules))
+	for _, key := range slices.Sorted(maps.Keys(entity.Rules)) {
+		rule := entity.Rules[key]
+		key = strings.TrimSpace(key)
+		if !slices.Contains(entity.Aliases, key) {
+			if pattern, err := ParsePattern(key); err == nil {
+				key = pattern.String()
+			}
+		}
+		rules[key] = rule
+	}
+
+	return rules
+}
+
+// checkRules returns an error if a rule matches neither an alias nor a pattern
+// of its entity, since it would never apply.
+func (source source) checkRules() error {
+	for _, entity := range source.entities {
+		for _, key := range slices.Sorted(maps.Keys(entity.Rules)) {
+			if slices.Contains(entity.Aliases, key) || slices.ContainsFunc(entity.Patterns, func(pattern Pattern) bool {
+				return pattern.String() == key
+			}) {
+				continue
+			}
+			return fmt.Errorf("entitydebs: rule %q of entity %q matches no alias or pattern", key, entity.ID)
+		}
+	}
+
+	return nil
+}
+
+// rule returns the rule of the alias or pattern of the entity with id.
+func (source source) rule(id, alias string) (Rule, bool) {
+	for _, entity := range source.entities {
+		if entity.ID != id {
+			continue
+		}
+		rule, ok := entity.Rules[alias]
+		return rule, ok
+	}
+
+	return Rule{}, false
+}
+
+// Rejections returns all matches that were rejected by a [Rule].
+func (f Frames) Rejections() iter.Seq[Rejection] {
+	return func(yield func(Rejection) bool) {
+		for i, frame := range f.frames {
+			for _, rejection := range frame.rejections {
+				rejection.Frame = i
+				if !yield(rejection) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package entitydebs
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/testhelper"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func TestRule_check(t *testing.T) {
+	t.Parallel()
+
+	// I prefer the morning flight through Denver.
+	tokens := testhelper.NewExampleTokens1(t, 0, 0)
+
+	tests := []struct {
+		name    string
+		rule    Rule
+		i       int
+		n       int
+		alias   []tokenize.Token
+		pattern Pattern
+		want    Reason
+	}{
+		{
+			name: "proper noun",
+			rule: Rule{Proper: true},
+			i:    6,
+			n:    1,
+		},
+		{
+			name: "not a proper noun",
+			rule: Rule{Proper: true},
+			i:    4,
+			n:    1,
+			want: ReasonProper,
+		},
+		{
+			name: "tag not allowed",
+			rule: Rule{Tags: []tokenize.PartOfSpeechTag{tokenize.PartOfSpeechTagNoun}},
+			i:    2,
+			n:    2,
+			want: ReasonTag,
+		},
+		{
+			name:  "alias case mismatch",
+			rule:  Rule{CaseSensitive: true},
+			i:     6,
+			n:     1,
+			alias: []tokenize.Token{*testhelper.NewToken(t, "denver", 0, nil, nil, "")},
+			want:  ReasonCase,
+		},
+		{
+			name:    "pattern case mismatch",
+			rule:    Rule{CaseSensitive: true},
+			i:       6,
+			n:       1,
+			pattern: MustParsePattern("denver"),
+			want:    ReasonCase,
+		},
+		{
+			name: "negative context before",
+			rule: Rule{NotBefore: []Pattern{MustParsePattern("the morning")}},
+			i:    4,
+			n:    1,
+			want: ReasonNotBefore,
+		},
+		{
+			name: "negative context not immediately before",
+			rule: Rule{NotBefore: []Pattern{MustParsePattern("the")}},
+			i:    4,
+			n:    1,
+		},
+		{
+			name: "negative context after",
+			rule: Rule{NotAfter: []Pattern{MustParsePattern("[PUNCT]")}},
+			i:    6,
+			n:    1,
+			want: ReasonNotAfter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.rule.check(tokens, tt.i, tt.n, tt.alias, tt.pattern, MatchExact); got != tt.want {
+				t.Errorf("Rule.check() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFramesRejections(t *testing.T) {
+	t.Parallel()
+
+	src := NewMultiSource([]Entity{
+		{
+			ID:      "us",
+			Aliases: []string{"US"},
+			Rules: map[string]Rule{
+				"US": {CaseSensitive: true},
+			},
+		},
+	}, []string{"Give us the US."}, WithMatch(MatchFold))
+	frames, err := src.Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	frame := frames.frames[0]
+	if diff := cmp.Diff(map[int]match{3: {id: "us", alias: "US"}}, frame.matches, cmp.AllowUnexported(match{})); diff != "" {
+		t.Errorf("source.Frames().frames[0].matches mismatch (-want +got):\n%s", diff)
+	}
+
+	want := []Rejection{
+		{
+			Frame:  0,
+			Offset: 1,
+			Entity: "us",
+			Alias:  "US",
+			Tokens: frame.tokens[1:2],
+			Reason: ReasonCase,
+		},
+	}
+	if diff := cmp.Diff(want, slices.Collect(frames.Rejections())); diff != "" {
+		t.Errorf("Frames.Rejections() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFramesRejectionsFallback(t *testing.T) {
+	t.Parallel()
+
+	src := NewMultiSource([]Entity{
+		{
+			ID:      "ny",
+			Aliases: []string{"New York", "New York Times"},
+			Rules: map[string]Rule{
+				"New York Times": {CaseSensitive: true},
+			},
+		},
+	}, []string{"I read the new york times."}, WithMatch(MatchFold))
+	frames, err := src.Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	frame := frames.frames[0]
+	if diff := cmp.Diff(map[int]match{3: {id: "ny", alias: "New York"}}, frame.matches, cmp.AllowUnexported(match{})); diff != "" {
+		t.Errorf("source.Frames().frames[0].matches mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(frame.tokens[3:5], frame.entities[3]); diff != "" {
+		t.Errorf("source.Frames().frames[0].entities mismatch (-want +got):\n%s", diff)
+	}
+	if got := slices.Collect(frames.Rejections()); len(got) != 1 || got[0].Alias != "New York Times" {
+		t.Errorf("Frames.Rejections() = %v, want a rejection of New York Times", got)
+	}
+}
+
+func TestFramesRulesSentence(t *testing.T) {
+	t.Parallel()
+
+	// Negative contexts don't cross sentences.
+	src := NewMultiSource([]Entity{
+		{
+			ID:      "us",
+			Aliases: []string{"US"},
+			Rules: map[string]Rule{
+				"US": {
+					NotBefore: []Pattern{MustParsePattern("Army [*]")},
+					NotAfter:  []Pattern{MustParsePattern("[*] Army")},
+				},
+			},
+		},
+	}, []string{"Go US. Army wins. Army. US waits."})
+	frames, err := src.Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	want := map[int]match{
+		1: {id: "us", alias: "US"},
+		8: {id: "us", alias: "US"},
+	}
+	if diff := cmp.Diff(want, frames.frames[0].matches, cmp.AllowUnexported(match{})); diff != "" {
+		t.Errorf("source.Frames().frames[0].matches mismatch (-want +got):\n%s", diff)
+	}
+	if got := slices.Collect(frames.Rejections()); len(got) != 0 {
+		t.Errorf("Frames.Rejections() = %v, want none", got)
+	}
+}
+
+func TestFramesRulesKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pattern in any form", func(t *testing.T) {
+		t.Parallel()
+
+		src := NewMultiSource([]Entity{
+			{
+				ID:       "us",
+				Patterns: []Pattern{MustParsePattern("[text=US]")},
+				Rules: map[string]Rule{
+					"[* text=US]": {CaseSensitive: true},
+				},
+			},
+		}, []string{"Give us the US."}, WithMatch(MatchFold))
+		frames, err := src.Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax)
+		if err != nil {
+			t.Fatalf("source.Frames() = _, %s, want nil", err)
+		}
+
+		want := map[int]match{3: {id: "us", alias: "US"}}
+		if diff := cmp.Diff(want, frames.frames[0].matches, cmp.AllowUnexported(match{})); diff != "" {
+			t.Errorf("source.Frames().frames[0].matches mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("no alias or pattern", func(t *testing.T) {
+		t.Parallel()
+
+		src := NewMultiSource([]Entity{
+			{
+				ID:      "us",
+				Aliases: []string{"US"},
+				Rules: map[string]Rule{
+					"USA": {CaseSensitive: true},
+				},
+			},
+		}, []string{"Give us the US."})
+		if _, err := src.Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax); err == nil {
+			t.Error("source.Frames() = _, nil, want error")
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package entitydebs
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type (
+	// span is a byte span of a text. The end is exclusive.
+	span struct {
+		begin, end int
+	}
+
+	// occurrence is an alias occurrence within a raw text.
+	occurrence struct {
+		span
+		// alias is the index of the occurring alias.
+		alias int
+	}
+)
+
+// scan returns the leftmost-longest, non-overlapping occurrences of aliases in
+// the raw text under the matching mode, without tokenizing it. Lemmas aren't
+// considered. If bounded is true, occurrences must begin and end at word
+// boundaries.
+func scan(text string, aliases []string, mode Match, bounded bool) []occurrence {
+	normalized := make([]string, len(aliases))
+	for i, alias := range aliases {
+		normalized[i] = mode.normalize(alias)
+	}
+	spans, segments := segment(text, mode)
+
+	occurrences := make([]occurrence, 0)
+	for s := 0; s < len(spans); {
+		var (
+			i     = spans[s].begin
+			found = occurrence{alias: -1}
+			next  = s + 1
+		)
+		if !bounded || boundary(text, i) {
+			for k, alias := range normalized {
+				if alias == "" {
+					continue
+				}
+				m, ok := hasPrefix(segments[s:], alias)
+				if !ok {
+					continue
+				}
+				end := spans[s+m-1].end
+				if end-i <= found.end-found.begin || (bounded && !boundary(text, end)) {
+					continue
+				}
+				found = occurrence{span: span{begin: i, end: end}, alias: k}
+				next = s + m
+			}
+		}
+
+		if found.alias >= 0 {
+			occurrences = append(occurrences, found)
+		}
+		s = next
+	}
+
+	return occurrences
+}
+
+// segment splits text into spans that are normalized on their own under the
+// matching mode, and returns the spans with their normalized forms. NFKC isn't
+// compositional, e.g., "e" followed by a combining acute accent composes to
+// "é", so under [MatchNFKC] spans end at normalization boundaries. Otherwise,
+// spans are runes.
+func segment(text string, mode Match) ([]span, []string) {
+	var (
+		spans    = make([]span, 0, len(text))
+		segments = make([]string, 0, len(text))
+	)
+	if mode&MatchNFKC != 0 {
+		var it norm.Iter
+		it.InitString(norm.NFKC, text)
+		for !it.Done() {
+			begin := it.Pos()
+			it.Next()
+			spans = append(spans, span{begin: begin, end: it.Pos()})
+		}
+	} else {
+		for i := 0; i < len(text); {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			spans = append(spans, span{begin: i, end: i + size})
+			i += size
+		}
+	}
+	for _, span := range spans {
+		segments = append(segments, mode.normalize(text[span.begin:span.end]))
+	}
+
+	return spans, segments
+}
+
+// hasPrefix reports whether the normalized segments begin with the normalized
+// alias. It returns the number of segments the alias spans.
+func hasPrefix(segments []string, alias string) (int, bool) {
+	var n, k int
+	for k < len(alias) {
+		if n == len(segments) {
+			return 0, false
+		}
+		s := segments[n]
+		if !strings.HasPrefix(alias[k:], s) {
+			return 0, false
+		}
+		n++
+		k += len(s)
+	}
+
+	return n, true
+}
+
+// boundary reports whether the byte offset i of text is a word boundary.
+func boundary(text string, i int) bool {
+	if i <= 0 || i >= len(text) {
+		return true
+	}
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	after, _ := utf8.DecodeRuneInString(text[i:])
+
+	return !word(before) || !word(after)
+}
+
+// word reports whether r is part of a word.
+func word(r rune) bool {
+	return unicode.In(r, unicode.Letter, unicode.Number, unicode.Mark)
+}
+
+// split cheaply splits the raw text into sentence spans. Sentences end at line
+// breaks and at sentence terminals followed by white space. Spans don't
+// contain surrounding white space.
+func split(text string) []span {
+	var (
+		spans = make([]span, 0)
+		begin = -1
+	)
+	for i, r := range text {
+		space := unicode.IsSpace(r)
+		if begin < 0 {
+			if !space {
+				begin = i
+			}
+			continue
+		}
+
+		end := -1
+		switch {
+		case r == '\n':
+			end = i
+		case space:
+			prev, _ := utf8.DecodeLastRuneInString(text[:i])
+			if unicode.Is(unicode.Sentence_Terminal, prev) {
+				end = i
+			}
+		}
+		if end >= 0 {
+			spans = append(spans, span{
+				begin: begin,
+				end:   len(strings.TrimRightFunc(text[:end], unicode.IsSpace)),
+			})
+			begin = -1
+		}
+	}
+	if begin >= 0 {
+		spans = append(spans, span{
+			begin: begin,
+			end:   len(strings.TrimRightFunc(text, unicode.IsSpace)),
+		})
+	}
+
+	return spans
+}
@@ -8,33 +8,158 @@ import (
 type (
 	// source wraps entities and texts, and returns a data [Frames].
 	source struct {
-		entity, texts []string
+		entities []Entity
+		texts    []string
+
+		// match is the entity matching mode.
+		match Match
+		// prefilter only tokenizes sentences with aliases and their window.
+		prefilter bool
+		window    int
+		// spans matches aliases on character spans of the raw text.
+		spans bool
+		// concurrency is the maximum number of concurrently tokenized texts.
+		concurrency int
+		// progress is called with the progress of a computation.
+		progress func(Progress)
+		// policy is the error policy.
+		policy ErrorPolicy
+		// checkpoint is the checkpoint directory, if any.
+		checkpoint string
+		// resume restores finished frames from the checkpoint directory.
+		resume bool
+		// metadata contains the metadata of each text, if any.
+		metadata []map[string]string
+		// annotations contains the annotated mentions of each text, if the
+		// source is annotated.
+		annotations [][]spanMatch
+	}
+
+	// Option configures a source.
+	Option func(*source)
+
+	// Entity is a named entity group, consisting of an identifier and its
+	// aliases.
+	Entity struct {
+		// ID identifies the entity. Every entity match carries the ID of its
+		// entity.
+		ID string
+		// Aliases contains the aliases of the entity. By convention, the first
+		// alias is the most well-known.
+		Aliases []string
+		// Patterns contains token patterns the entity is additionally defined
+		// by, see [ParsePattern].
+		Patterns []Pattern
+		// Rules contains disambiguation rules, keyed by alias or by pattern.
+		// Patterns are compared in their parsed form, so "[lemma=run VERB]"
+		// and "[VERB lemma=run]" are the same key. Keys that match no alias
+		// or pattern are an error.
+		Rules map[string]Rule
 	}
 )
 
 // NewSource returns a new source, consisting of the entity, its aliases and
 // texts. Duplicate entities and surrounding white spaces are removed.
 //
-// By convention, the first entity is the most well-known.
-func NewSource(entity, texts []string) source {
-	// De-duplicate entities
-	dedup := make([]string, len(entity))
-	for i, alias := range entity {
-		if !slices.Contains(dedup, alias) {
-			dedup[i] = alias
+// By convention, the first entity is the most well-known. It is used as the
+// entity ID.
+func NewSource(entity, texts []string, opts ...Option) source {
+	aliases := dedup(entity)
+
+	entities := make([]Entity, 0, 1)
+	if len(aliases) > 0 {
+		entities = append(entities, Entity{
+			ID:      aliases[0],
+			Aliases: aliases,
+		})
+	}
+
+	source := source{
+		entities: entities,
+		texts:    trim(texts),
+	}
+	for _, opt := range opts {
+		opt(&source)
+	}
+
+	return source
+}
+
+// NewMultiSource returns a new source, consisting of multiple entities with
+// their aliases and texts. All entities are analyzed in one pass. Duplicate
+// aliases and surrounding white spaces are removed. An alias belongs to the
+// first entity that declares it.
+func NewMultiSource(entities []Entity, texts []string, opts ...Option) source {
+	var (
+		groups = make([]Entity, 0, len(entities))
+		seen   = make([]string, 0)
+	)
+	for _, entity := range entities {
+		aliases := slices.DeleteFunc(dedup(entity.Aliases), func(alias string) bool {
+			return slices.Contains(seen, alias)
+		})
+		seen = append(seen, aliases...)
+
+		group := Entity{
+			ID:       entity.ID,
+			Aliases:  aliases,
+			Patterns: entity.Patterns,
+			Rules:    entity.Rules,
 		}
+		group.Rules = canonicalRules(group)
+		groups = append(groups, group)
+	}
+
+	source := source{
+		entities: groups,
+		texts:    trim(texts),
 	}
+	for _, opt := range opts {
+		opt(&source)
+	}
+
+	return source
+}
+
+// WithMetadata sets the metadata of each text, e.g., the speaker or date.
+// metadata is aligned with texts by index, texts without metadata have none.
+// Metadata is carried into the frame of its text. Streams can pass metadata
+// along with every text instead, see [NewMultiStream].
+func WithMetadata(metadata []map[string]string) Option {
+	return func(source *source) {
+		source.metadata = metadata
+	}
+}
 
-	// Trim leading and trailing white space.
-	for i, alias := range dedup {
-		dedup[i] = strings.TrimSpace(alias)
+// metadataOf returns the metadata of text i, if any.
+func (source source) metadataOf(i int) map[string]string {
+	if i < 0 || i >= len(source.metadata) {
+		return nil
 	}
+
+	return source.metadata[i]
+}
+
+// dedup returns the trimmed, de-duplicated and non-empty aliases.
+func dedup(aliases []string) []string {
+	d := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		// Trim leading and trailing white space.
+		alias = strings.TrimSpace(alias)
+		if alias == "" || slices.Contains(d, alias) {
+			continue
+		}
+		d = append(d, alias)
+	}
+
+	return d
+}
+
+// trim trims leading and trailing white space of texts in place.
+func trim(texts []string) []string {
 	for i, text := range texts {
 		texts[i] = strings.TrimSpace(text)
 	}
 
-	return source{
-		entity: dedup,
-		texts:  texts,
-	}
+	return texts
 }
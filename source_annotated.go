@@ -0,0 +1,87 @@
+package entitydebs
+
+import (
+	"cmp"
+	"slices"
+)
+
+type (
+	// Document is a text with annotated entity mentions, e.g., exported from
+	// brat or INCEpTION.
+	Document struct {
+		Text string
+		// Annotations contains the entity mentions of the text.
+		Annotations []Annotation
+		// Metadata contains the metadata of the text, see [WithMetadata].
+		Metadata map[string]string
+	}
+
+	// Annotation is an entity mention, given by byte offsets of the text.
+	Annotation struct {
+		// Begin is the byte offset of the first character of the mention.
+		Begin int
+		// End is the byte offset after the last character of the mention.
+		End int
+		// Entity is the entity ID.
+		Entity string
+	}
+)
+
+// NewAnnotatedSource returns a new source of documents with annotated entity
+// mentions. Instead of matching aliases, annotations are mapped onto the
+// tokens they overlap, so patterns and rules don't apply. Texts aren't
+// trimmed, so offsets stay valid. Annotations outside of their text are
+// dropped, of overlapping annotations the first is kept.
+//
+// Entities are identified by the annotated IDs, in order of appearance. The
+// tokenizer must provide byte offsets.
+func NewAnnotatedSource(docs []Document, opts ...Option) source {
+	var (
+		entities    = make([]Entity, 0)
+		texts       = make([]string, 0, len(docs))
+		annotations = make([][]spanMatch, 0, len(docs))
+		metadata    = make([]map[string]string, 0, len(docs))
+	)
+	for _, doc := range docs {
+		sorted := slices.SortedStableFunc(slices.Values(doc.Annotations), func(a, b Annotation) int {
+			return cmp.Or(cmp.Compare(a.Begin, b.Begin), cmp.Compare(b.End, a.End))
+		})
+
+		matches := make([]spanMatch, 0, len(sorted))
+		for _, annotation := range sorted {
+			if annotation.Begin < 0 || annotation.End > len(doc.Text) || annotation.Begin >= annotation.End {
+				continue
+			}
+			if len(matches) > 0 && annotation.Begin < matches[len(matches)-1].end {
+				continue
+			}
+
+			if !slices.ContainsFunc(entities, func(entity Entity) bool {
+				return entity.ID == annotation.Entity
+			}) {
+				entities = append(entities, Entity{ID: annotation.Entity})
+			}
+			matches = append(matches, spanMatch{
+				span:  span{begin: annotation.Begin, end: annotation.End},
+				id:    annotation.Entity,
+				alias: doc.Text[annotation.Begin:annotation.End],
+			})
+		}
+
+		texts = append(texts, doc.Text)
+		annotations = append(annotations, matches)
+		metadata = append(metadata, doc.Metadata)
+	}
+
+	source := source{
+		entities:    entities,
+		texts:       texts,
+		annotations: annotations,
+		metadata:    metadata,
+	}
+	for _, opt := range opts {
+		opt(&source)
+	}
+
+	return source
+}
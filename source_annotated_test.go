@@ -0,0 +1,58 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func Test_sourceFramesAnnotated(t *testing.T) {
+	t.Parallel()
+
+	docs := []Document{
+		{
+			Text: "The U.S. and Washington met.",
+			Annotations: []Annotation{
+				{Begin: 13, End: 23, Entity: "washington"},
+				{Begin: 4, End: 8, Entity: "us"},
+				// Overlapping
+				{Begin: 5, End: 10, Entity: "other"},
+				// Out of bounds
+				{Begin: 20, End: 99, Entity: "other"},
+			},
+		},
+		{
+			Text: "Nothing here.",
+		},
+	}
+
+	tokenizer := newOffsetTokenizer()
+	src := NewAnnotatedSource(docs, WithMatch(MatchFold))
+	frames, err := src.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	// Only texts are tokenized.
+	if got := len(*tokenizer.texts); got != len(docs) {
+		t.Errorf("len(tokenized texts) = %d, want %d", got, len(docs))
+	}
+	if diff := cmp.Diff([]Entity{{ID: "us"}, {ID: "washington"}}, src.entities); diff != "" {
+		t.Errorf("NewAnnotatedSource().entities mismatch (-want +got):\n%s", diff)
+	}
+
+	want := map[int]match{
+		1: {id: "us", alias: "U.S."},
+		6: {id: "washington", alias: "Washington"},
+	}
+	if diff := cmp.Diff(want, frames.frames[0].matches, cmp.AllowUnexported(match{})); diff != "" {
+		t.Errorf("source.Frames().frames[0].matches mismatch (-want +got):\n%s", diff)
+	}
+	if got := len(frames.frames[0].entities[1]); got != 4 {
+		t.Errorf("len(source.Frames().frames[0].entities[1]) = %d, want 4", got)
+	}
+	if got := len(frames.frames[1].matches); got != 0 {
+		t.Errorf("len(source.Frames().frames[1].matches) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,106 @@
+package entitydebs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WithCheckpoint writes every finished frame to the directory dir, so an
+// interrupted computation can be resumed with [WithResume]. Frames are
+// identified by a SHA-256 hash of their text and annotations, if any.
+//
+// Checkpoints must be resumed with the same entities, options, features and
+// normalizers, otherwise frames differ from an uninterrupted computation.
+func WithCheckpoint(dir string) Option {
+	return func(source *source) {
+		source.checkpoint = dir
+	}
+}
+
+// WithResume restores frames of texts that are already done from the
+// checkpoint directory instead of tokenizing them, see [WithCheckpoint].
+func WithResume() Option {
+	return func(source *source) {
+		source.resume = true
+	}
+}
+
+// checkpointKey returns the stable checkpoint key of text and annotations.
+func checkpointKey(text string, annotations []spanMatch) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(text))
+	for _, annotation := range annotations {
+		_, _ = fmt.Fprintf(h, "\x00%d:%d:%s", annotation.begin, annotation.end, annotation.id)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path returns the checkpoint file path of key.
+func (source source) path(key string) string {
+	return filepath.Join(source.checkpoint, key+".json")
+}
+
+// restore returns the checkpointed frame of key, if resuming and the frame
+// exists.
+func (source source) restore(key string) (frame, bool, error) {
+	if source.checkpoint == "" || !source.resume {
+		return frame{}, false, nil
+	}
+
+	b, err := os.ReadFile(source.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return frame{}, false, nil
+	}
+	if err != nil {
+		return frame{}, false, err
+	}
+	var fj frameJSON
+	if err := json.Unmarshal(b, &fj); err != nil {
+		return frame{}, false, fmt.Errorf("entitydebs: checkpoint %s: %w", key, err)
+	}
+	f, err := fj.unmarshal()
+	if err != nil {
+		return frame{}, false, fmt.Errorf("entitydebs: checkpoint %s: %w", key, err)
+	}
+
+	return f, true, nil
+}
+
+// save writes f to the checkpoint file of key, if checkpointing is enabled.
+// The file is replaced atomically, so interruptions never leave partial
+// checkpoints.
+func (source source) save(key string, f frame) error {
+	if source.checkpoint == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(source.checkpoint, 0o755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(f.marshal())
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(source.checkpoint, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), source.path(key))
+}
@@ -0,0 +1,79 @@
+package entitydebs
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func Test_sourceFramesCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir   = t.TempDir()
+		texts = []string{"Valhalla is far.", "Far far away.", "Valhalla and Valhalla."}
+		alias = []string{"Valhalla"}
+	)
+
+	// Uninterrupted
+	want, err := NewSource(alias, slices.Clone(texts)).Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	// Interrupted at the second text
+	interrupt := errors.New("interrupted")
+	src := NewSource(alias, slices.Clone(texts), WithCheckpoint(dir))
+	if _, err := src.Frames(t.Context(), failTokenizer{fail: texts[1], err: interrupt, next: newOffsetTokenizer()}, tokenize.FeatureSyntax); !errors.Is(err, interrupt) {
+		t.Fatalf("source.Frames() = _, %v, want %s", err, interrupt)
+	}
+
+	// Resumed
+	tokenizer := newOffsetTokenizer()
+	src = NewSource(alias, slices.Clone(texts), WithCheckpoint(dir), WithResume())
+	got, err := src.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+	if diff := cmp.Diff([]string{alias[0], texts[1], texts[2]}, *tokenizer.texts); diff != "" {
+		t.Errorf("tokenized texts mismatch (-want +got):\n%s", diff)
+	}
+
+	marshal := func(frames Frames) []frameJSON {
+		fj := make([]frameJSON, 0, len(frames.frames))
+		for _, f := range frames.frames {
+			fj = append(fj, f.marshal())
+		}
+		return fj
+	}
+	if diff := cmp.Diff(marshal(want), marshal(got)); diff != "" {
+		t.Errorf("source.Frames() mismatch (-want +got):\n%s", diff)
+	}
+	for i, f := range got.frames {
+		if f.index != i {
+			t.Errorf("source.Frames().frames[%d].index = %d, want %d", i, f.index, i)
+		}
+	}
+
+	t.Run("corrupt checkpoint", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			dir = t.TempDir()
+			src = NewSource(alias, slices.Clone(texts[:1]), WithCheckpoint(dir), WithResume())
+			key = checkpointKey(texts[0], nil)
+		)
+		if err := os.WriteFile(src.path(key), []byte("{"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		want := "entitydebs: checkpoint " + key + ": "
+		if _, err := src.Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("source.Frames() = _, %v, want %s...", err, want)
+		}
+	})
+}
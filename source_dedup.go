@@ -0,0 +1,232 @@
+package entitydebs
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	"golang.org/x/text/cases"
+)
+
+// Duplicates configures the near-duplicate detection of [source.Deduplicate].
+type Duplicates struct {
+	// Threshold is the minimum estimated Jaccard similarity of the shingles of
+	// two texts to be duplicates. Defaults to 0.8. A threshold of 1 only
+	// detects texts with equal shingles.
+	Threshold float64
+	// Shingle is the number of consecutive words of a shingle. Defaults to 5.
+	Shingle int
+	// Drop drops all texts of a duplicate group. Otherwise, groups are
+	// collapsed to their first text.
+	Drop bool
+}
+
+const (
+	// minhashes is the number of MinHash functions of a signature.
+	minhashes = 128
+	// minhashSeed seeds the MinHash functions, so signatures are stable
+	// across runs.
+	minhashSeed = 0x656e7469747964
+)
+
+// Deduplicate detects exact and near-duplicate texts before tokenization
+// using MinHash and locality-sensitive hashing over word shingles. Words are
+// compared case-insensitively.
+//
+// It returns a source without duplicates and all duplicate groups. A group
+// contains the ascending text indices of source. Groups are ordered by their
+// first text.
+func (source source) Deduplicate(dups Duplicates) (source, [][]int) {
+	threshold := dups.Threshold
+	if threshold <= 0 {
+		threshold = 0.8
+	}
+	shingle := dups.Shingle
+	if shingle <= 0 {
+		shingle = 5
+	}
+
+	// Compute signatures.
+	var (
+		seeds      = minhashSeeds()
+		signatures = make([][minhashes]uint64, len(source.texts))
+	)
+	for i, text := range source.texts {
+		signatures[i] = signature(shingles(text, shingle), seeds)
+	}
+
+	// Find candidates with locality-sensitive hashing. Texts that share a
+	// band bucket are candidates, candidates above the threshold are joined.
+	var (
+		bands, rows = lshBands(threshold)
+		parent      = make([]int, len(source.texts))
+	)
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	for band := range bands {
+		buckets := make(map[uint64][]int)
+		for i, sig := range signatures {
+			h := fnv.New64a()
+			for _, v := range sig[band*rows : (band+1)*rows] {
+				_ = binary.Write(h, binary.LittleEndian, v)
+			}
+			key := h.Sum64()
+
+			// A bucket keeps one text per group, so clusters of identical
+			// texts need a linear number of comparisons.
+			represented := false
+			for _, j := range buckets[key] {
+				if a, b := find(i), find(j); a != b && similarity(signatures[i], signatures[j]) >= threshold {
+					// Join groups, the lowest index is the root.
+					parent[max(a, b)] = min(a, b)
+				}
+				represented = represented || find(i) == find(j)
+			}
+			if !represented {
+				buckets[key] = append(buckets[key], i)
+			}
+		}
+	}
+
+	// Collect groups.
+	var (
+		members = make(map[int][]int)
+		roots   = make([]int, 0)
+	)
+	for i := range source.texts {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+	// Roots are the first texts of their groups, so the text order is kept.
+	var (
+		groups      = make([][]int, 0)
+		texts       = make([]string, 0, len(source.texts))
+		annotations [][]spanMatch
+		metadata    []map[string]string
+	)
+	for _, root := range roots {
+		group := members[root]
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+		if len(group) > 1 && dups.Drop {
+			continue
+		}
+		texts = append(texts, source.texts[group[0]])
+		if source.annotations != nil {
+			annotations = append(annotations, source.annotations[group[0]])
+		}
+		if source.metadata != nil {
+			metadata = append(metadata, source.metadataOf(group[0]))
+		}
+	}
+	source.texts = texts
+	source.annotations = annotations
+	source.metadata = metadata
+
+	return source, groups
+}
+
+// shingles returns the hashed word shingles of text. Texts with less words
+// than a shingle have a single shingle.
+func shingles(text string, n int) []uint64 {
+	words := strings.Fields(cases.Fold().String(text))
+	if len(words) < n {
+		n = len(words)
+	}
+
+	hashes := make([]uint64, 0, len(words)-n+1)
+	for i := 0; i+n <= len(words); i++ {
+		h := fnv.New64a()
+		for _, word := range words[i : i+n] {
+			_, _ = h.Write([]byte(word))
+			_, _ = h.Write([]byte{0})
+		}
+		hashes = append(hashes, h.Sum64())
+	}
+
+	return hashes
+}
+
+// minhashSeeds returns the seeds of all MinHash functions.
+func minhashSeeds() [minhashes]uint64 {
+	var (
+		seeds [minhashes]uint64
+		rng   = rand.New(rand.NewPCG(minhashSeed, minhashSeed))
+	)
+	for i := range seeds {
+		seeds[i] = rng.Uint64()
+	}
+
+	return seeds
+}
+
+// signature returns the MinHash signature of shingles.
+func signature(shingles []uint64, seeds [minhashes]uint64) [minhashes]uint64 {
+	var sig [minhashes]uint64
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for _, shingle := range shingles {
+		for i, seed := range seeds {
+			sig[i] = min(sig[i], mix(shingle^seed))
+		}
+	}
+
+	return sig
+}
+
+// similarity estimates the Jaccard similarity of two signatures.
+func similarity(a, b [minhashes]uint64) float64 {
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+
+	return float64(equal) / minhashes
+}
+
+// lshBands returns the number of bands and rows per band whose candidate
+// threshold (1/bands)^(1/rows) is closest to, but not above threshold.
+func lshBands(threshold float64) (bands, rows int) {
+	bands, rows = minhashes, 1
+	for r := 1; r <= minhashes; r++ {
+		if minhashes%r != 0 {
+			continue
+		}
+		b := minhashes / r
+		t := math.Pow(1/float64(b), 1/float64(r))
+		if t > threshold {
+			break
+		}
+		bands, rows = b, r
+	}
+
+	return bands, rows
+}
+
+// mix is the SplitMix64 finalizer.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
@@ -0,0 +1,93 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_sourceDeduplicate(t *testing.T) {
+	t.Parallel()
+
+	texts := []string{
+		"The quick brown fox jumps over the lazy dog near the river bank today.",
+		"Completely different speech about budget policy and taxes in the parliament.",
+		"The quick brown fox jumps over the lazy dog near the river bank today.",
+		"the quick brown fox jumps over the lazy dog near the river bank today!",
+		"",
+		"Mr. President, I yield back.",
+		"  ",
+	}
+
+	tests := []struct {
+		name   string
+		dups   Duplicates
+		texts  []string
+		groups [][]int
+	}{
+		{
+			name: "collapse",
+			dups: Duplicates{Threshold: 0.6, Shingle: 3},
+			texts: []string{
+				texts[0],
+				texts[1],
+				texts[4],
+				texts[5],
+			},
+			groups: [][]int{{0, 2, 3}, {4, 6}},
+		},
+		{
+			name: "drop",
+			dups: Duplicates{Threshold: 0.6, Shingle: 3, Drop: true},
+			texts: []string{
+				texts[1],
+				texts[5],
+			},
+			groups: [][]int{{0, 2, 3}, {4, 6}},
+		},
+		{
+			name: "exact",
+			dups: Duplicates{Threshold: 1},
+			texts: []string{
+				texts[0],
+				texts[1],
+				texts[3],
+				texts[4],
+				texts[5],
+			},
+			groups: [][]int{{0, 2}, {4, 6}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src, groups := NewSource(nil, append([]string(nil), texts...)).Deduplicate(tt.dups)
+			if diff := cmp.Diff(tt.texts, src.texts); diff != "" {
+				t.Errorf("source.Deduplicate() texts mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.groups, groups); diff != "" {
+				t.Errorf("source.Deduplicate() groups mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_sourceDeduplicateCluster(t *testing.T) {
+	t.Parallel()
+
+	// Boilerplate that is repeated verbatim. Comparing every pair of texts
+	// would take minutes.
+	texts := make([]string, 50000)
+	for i := range texts {
+		texts[i] = "Mr. President, I yield back the balance of my time."
+	}
+
+	src, groups := NewSource(nil, texts).Deduplicate(Duplicates{})
+	if got := len(src.texts); got != 1 {
+		t.Errorf("len(source.Deduplicate().texts) = %d, want 1", got)
+	}
+	if len(groups) != 1 || len(groups[0]) != len(texts) {
+		t.Errorf("source.Deduplicate() groups = %d, want one group of %d texts", len(groups), len(texts))
+	}
+}
@@ -0,0 +1,42 @@
+package entitydebs
+
+import (
+	"fmt"
+)
+
+type (
+	// ErrorPolicy determines how a [Frames] computation handles texts that
+	// fail to tokenize.
+	ErrorPolicy int
+
+	// TextError is the error of a text that failed to tokenize.
+	TextError struct {
+		// Index is the index of the text.
+		Index int
+		// Err is the cause.
+		Err error
+	}
+)
+
+const (
+	// ErrorPolicyAbort aborts on the first failed text. This is the default.
+	ErrorPolicyAbort ErrorPolicy = iota
+	// ErrorPolicySkip skips failed texts and continues with the remaining
+	// ones. All failures are returned joined, see [errors.Join].
+	ErrorPolicySkip
+)
+
+// WithErrorPolicy sets the error policy.
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(source *source) {
+		source.policy = policy
+	}
+}
+
+func (err *TextError) Error() string {
+	return fmt.Sprintf("text %d: %s", err.Index, err.Err)
+}
+
+func (err *TextError) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,85 @@
+package entitydebs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func Test_sourceFramesErrorPolicy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		cause     = errors.New("unsupported language")
+		tokenizer = failTokenizer{fail: "Two.", err: cause}
+		texts     = []string{"One.", "Two.", "Three.", "Two."}
+	)
+
+	t.Run("skip", func(t *testing.T) {
+		t.Parallel()
+
+		src := NewSource([]string{"Valhalla"}, slices.Clone(texts), WithErrorPolicy(ErrorPolicySkip), WithConcurrency(2))
+		frames, err := src.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax)
+		if !errors.Is(err, cause) {
+			t.Fatalf("source.Frames() = _, %v, want %s", err, cause)
+		}
+
+		indices := make([]int, 0)
+		for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var e *TextError
+			if !errors.As(err, &e) {
+				t.Fatalf("source.Frames() error %v is not a *TextError", err)
+			}
+			indices = append(indices, e.Index)
+		}
+		if want := []int{1, 3}; !slices.Equal(indices, want) {
+			t.Errorf("source.Frames() error indices = %v, want %v", indices, want)
+		}
+
+		got := make([]int, 0)
+		for _, f := range frames.frames {
+			got = append(got, f.index)
+		}
+		if want := []int{0, 2}; !slices.Equal(got, want) {
+			t.Errorf("source.Frames() frame indices = %v, want %v", got, want)
+		}
+	})
+	t.Run("abort", func(t *testing.T) {
+		t.Parallel()
+
+		src := NewSource([]string{"Valhalla"}, slices.Clone(texts))
+		frames, err := src.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax)
+		var e *TextError
+		if !errors.As(err, &e) || e.Index != 1 {
+			t.Fatalf("source.Frames() = _, %v, want text 1 error", err)
+		}
+		if got := len(frames.frames); got != 1 {
+			t.Errorf("len(source.Frames().frames) = %d, want 1", got)
+		}
+	})
+	t.Run("stream", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			stream = NewStream([]string{"Valhalla"}, slices.Values(texts), WithErrorPolicy(ErrorPolicySkip))
+			got    = make([]int, 0)
+			errs   = make([]int, 0)
+		)
+		for frames, err := range stream.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax) {
+			var e *TextError
+			if errors.As(err, &e) {
+				errs = append(errs, e.Index)
+				continue
+			}
+			got = append(got, frames.frames[0].index)
+		}
+		if want := []int{0, 2}; !slices.Equal(got, want) {
+			t.Errorf("stream.Frames() frame indices = %v, want %v", got, want)
+		}
+		if want := []int{1, 3}; !slices.Equal(errs, want) {
+			t.Errorf("stream.Frames() error indices = %v, want %v", errs, want)
+		}
+	})
+}
@@ -1,20 +1,36 @@
 package entitydebs
 
 import (
+	"cmp"
 	"context"
-	"iter"
-	"maps"
+	"errors"
 	"slices"
 
 	"github.com/ndabAP/entitydebs/tokenize"
+	"golang.org/x/sync/errgroup"
 )
 
+// WithConcurrency tokenizes up to n texts concurrently. Frames keep the
+// order of texts. Normalizers must then be safe for concurrent use. Defaults
+// to one.
+func WithConcurrency(n int) Option {
+	return func(source *source) {
+		source.concurrency = max(n, 1)
+	}
+}
+
 // Frames tokenizes all texts and entities of source into [Frames]
 // according to tokenizer. [Frames] is a collection of data frames and
 // entities within data frames.
 //
+// Texts are tokenized concurrently, see [WithConcurrency]. Errors of texts
+// are returned as [*TextError]. By default, the first error cancels all
+// pending texts, frames then contains all frames up to the first unfinished
+// text. See [WithErrorPolicy] to skip failed texts instead.
+//
 // [Normalizer] can be used to to reduce redundancy and improve data integrity.
-// Normalizers are not applied to entity tokens.
+// Normalizers are not applied to entity tokens, use [WithMatch] to match
+// entities case-insensitively, normalized or by lemma instead.
 func (source source) Frames(
 	ctx context.Context,
 	tokenizer tokenize.Tokenizer,
@@ -24,75 +40,215 @@ func (source source) Frames(
 	frames Frames,
 	err error,
 ) {
-	// Tokenize entities.
-	entities := make(map[string][]tokenize.Token, len(source.entity))
-	for _, entity := range source.entity {
-		select {
-		case <-ctx.Done():
-			return frames, ctx.Err()
-		default:
-		}
+	progress := source.newProgress(len(source.texts))
+	ctx = progress.context(ctx)
 
-		analysis, err := tokenizer.Tokenize(ctx, entity, tokenize.FeatureSyntax)
-		if err != nil {
-			return frames, err
-		}
-		for _, token := range analysis.Tokens {
-			entities[entity] = append(entities[entity], *token.Clone())
-		}
+	// Tokenize entities.
+	entities, ids, err := source.tokenizeEntities(ctx, tokenizer)
+	if err != nil {
+		return frames, err
 	}
 	frames.entities = entities
+	frames.ids = ids
+	aliases := newTrie(source.entities, entities, source.match)
 
-	// Tokenize texts into data frames.
-	frames.frames = make([]frame, 0, len(source.texts))
-	for _, text := range source.texts {
-		select {
-		case <-ctx.Done():
-			return frames, ctx.Err()
-		default:
+	// Tokenize texts into data frames. Every text has its own slot, so the
+	// order of texts is kept.
+	var (
+		done = make([]bool, len(source.texts))
+		errs = make([]error, len(source.texts))
+		g, c = errgroup.WithContext(ctx)
+	)
+	frames.frames = make([]frame, len(source.texts))
+	g.SetLimit(max(source.concurrency, 1))
+	for i, text := range source.texts {
+		g.Go(func() error {
+			select {
+			case <-c.Done():
+				return c.Err()
+			default:
+			}
+
+			var annotations []spanMatch
+			if source.annotations != nil {
+				annotations = source.annotations[i]
+			}
+			// Restore or compute and save the frame.
+			key := checkpointKey(text, annotations)
+			f, ok, err := source.restore(key)
+			if err == nil && !ok {
+				f, err = source.frame(c, tokenizer, text, annotations, aliases, feats, normalizer...)
+				if err == nil {
+					err = source.save(key, f)
+				}
+			}
+			if err != nil {
+				err = &TextError{Index: i, Err: err}
+				// Cancellations abort regardless of the policy.
+				if source.policy != ErrorPolicySkip || c.Err() != nil {
+					return err
+				}
+				errs[i] = err
+				progress.fail()
+				return nil
+			}
+			f.index = i
+			f.metadata = source.metadataOf(i)
+			frames.frames[i] = f
+			done[i] = true
+			progress.text(f)
+
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		// Keep all frames up to the first unfinished text.
+		n := 0
+		for n < len(done) && done[n] {
+			n++
 		}
+		frames.frames = frames.frames[:n]
 
-		f, err := source.frame(ctx, tokenizer, text, entities, feats, normalizer...)
-		if err != nil {
-			return frames, err
+		return frames, err
+	}
+	// Remove skipped texts.
+	if source.policy == ErrorPolicySkip {
+		finished := make([]frame, 0, len(frames.frames))
+		for i, f := range frames.frames {
+			if done[i] {
+				finished = append(finished, f)
+			}
 		}
-		frames.frames = append(frames.frames, f)
+		frames.frames = finished
+	}
+
+	return frames, errors.Join(errs...)
+}
 
+// tokenizeEntities tokenizes all entity aliases of source. It returns the
+// tokens and the entity ID of each alias. Aliases matched on spans aren't
+// tokenized.
+func (source source) tokenizeEntities(
+	ctx context.Context,
+	tokenizer tokenize.Tokenizer,
+) (
+	entities map[string][]tokenize.Token,
+	ids map[string]string,
+	err error,
+) {
+	entities = make(map[string][]tokenize.Token)
+	ids = make(map[string]string)
+	if err = source.checkRules(); err != nil {
+		return entities, ids, err
 	}
+	for _, entity := range source.entities {
+		for _, alias := range entity.Aliases {
+			ids[alias] = entity.ID
+			// Aliases are matched on the raw text.
+			if source.spans {
+				continue
+			}
 
-	return
+			select {
+			case <-ctx.Done():
+				return entities, ids, ctx.Err()
+			default:
+			}
+
+			analysis, err := tokenizer.Tokenize(ctx, alias, tokenize.FeatureSyntax)
+			if err != nil {
+				return entities, ids, err
+			}
+			for _, token := range analysis.Tokens {
+				entities[alias] = append(entities[alias], *token.Clone())
+			}
+		}
+	}
+
+	return entities, ids, nil
 }
 
-// frame computes a single data frame.
+// frame computes a single data frame. annotations contains the annotated
+// mentions of text, if source is annotated.
 func (source source) frame(
 	ctx context.Context,
 	tokenizer tokenize.Tokenizer,
 	text string,
-	entities map[string][]tokenize.Token,
+	annotations []spanMatch,
+	aliases *trie,
 	feats tokenize.Features,
 	normalizer ...Normalizer,
 ) (
 	frame frame,
 	err error,
 ) {
-	analysis, err := tokenizer.Tokenize(ctx, text, feats)
+	analysis, err := source.tokenize(ctx, tokenizer, text, annotations, feats)
 	if err != nil {
 		return
 	}
 
-	frame.tokens = make([]*tokenize.Token, len(analysis.Tokens))
-	frame.sentences = make([]*tokenize.Sentence, len(analysis.Sentences))
+	// Patterns and rules don't match across sentences.
 	frame.sentences = analysis.Sentences
+	frame.tokens = analysis.Tokens
+	starts, ends := frame.sentenceBounds()
+
+	frame.tokens = make([]*tokenize.Token, len(analysis.Tokens))
 	frame.entities = make(map[int][]*tokenize.Token, 0)
+	frame.matches = make(map[int]match, 0)
 	frame.sentiment = analysis.Sentiment
+	frame.language = analysis.Language
+
+	// Keys of all tokens under the matching mode.
+	keys := make([]string, len(analysis.Tokens))
+	for i, token := range analysis.Tokens {
+		keys[i] = source.match.key(token)
+	}
+
+	// Annotated mentions or alias occurrences on the raw text, if any.
+	var spans map[int]spanMatch
+	switch {
+	case source.annotations != nil:
+		spans = mapSpans(annotations, analysis.Tokens)
+	case source.spans:
+		spans = source.matchSpans(text, analysis.Tokens)
+	}
 
 	i := 0
 	for i != len(analysis.Tokens) {
-		// Peek for entity tokens.
-		_, tokens, j := peek(analysis.Tokens[i:], entities)
-		switch j {
+		// Take the longest candidate the rules of its entity accept.
+		var c candidate
+		for _, m := range source.candidates(text, analysis.Tokens, keys, i, ends[i], spans, aliases) {
+			rule, ok := source.rule(m.id, m.alias)
+			if !ok {
+				c = m
+				break
+			}
+			// Alias matches may span sentences.
+			sentence := analysis.Tokens[starts[i]:ends[i+m.n-1]]
+			reason := rule.check(sentence, i-starts[i], m.n, m.tokens, m.pattern, source.match)
+			// Span matches are compared on the raw text.
+			if reason == 0 && rule.CaseSensitive && m.raw != "" && m.raw != m.alias {
+				reason = ReasonCase
+			}
+			if reason == 0 {
+				c = m
+				break
+			}
+			// Fall back to the next longest candidate.
+			frame.rejections = append(frame.rejections, Rejection{
+				Offset: i,
+				Entity: m.id,
+				Alias:  m.alias,
+				Tokens: analysis.Tokens[i : i+m.n],
+				Reason: reason,
+			})
+		}
+		id, alias, n := c.id, c.alias, c.n
+		tokens := analysis.Tokens[i : i+n]
+
+		switch n {
 		// No entity tokens found.
-		case -1:
+		case 0:
 			// Append only to tokens.
 			token := analysis.Tokens[i]
 			frame.tokens[i] = token
@@ -111,6 +267,10 @@ func (source source) frame(
 				frame.tokens[i+k] = t
 				frame.entities[i] = append(frame.entities[i], t)
 			}
+			frame.matches[i] = match{
+				id:    id,
+				alias: alias,
+			}
 
 			// Skip about entity positions.
 			i += len(tokens)
@@ -120,63 +280,66 @@ func (source source) frame(
 	return
 }
 
-// peek checks if subsequent tokens are entity tokens. It returns the entity as
-// string, as tokens and the final index. If no entity was found, peek returns
-// -1.
-func peek(tokens []*tokenize.Token, entities map[string][]tokenize.Token) (string, []*tokenize.Token, int) {
-	// i contains the final entity index.
-	i := 0
+// candidate is a possible entity match at a token position.
+type candidate struct {
+	id, alias string
+	// tokens contains the alias tokens, pattern the matched pattern and raw
+	// the raw text of span matches.
+	tokens  []tokenize.Token
+	pattern Pattern
+	raw     string
+	// n is the number of matched tokens.
+	n int
+}
 
-	// Entity alias iterator
-	next, stop := iter.Pull2(maps.All(entities))
-	defer stop()
-	for {
-		found := true
-		entity, toks, ok := next()
-		if !ok {
-			break
+// candidates returns all aliases and patterns that match at token i, with keys
+// being the token keys under the matching mode. Patterns match up to token end.
+// Span matches, if any, replace alias matches. Longer candidates come first,
+// aliases take precedence over patterns of the same length and patterns are
+// ordered by declaration.
+func (source source) candidates(
+	text string,
+	tokens []*tokenize.Token,
+	keys []string,
+	i, end int,
+	spans map[int]spanMatch,
+	aliases *trie,
+) []candidate {
+	candidates := make([]candidate, 0)
+	if spans != nil {
+		if span, ok := spans[i]; ok {
+			candidates = append(candidates, candidate{
+				id:    span.id,
+				alias: span.alias,
+				raw:   text[span.begin:span.end],
+				n:     span.n,
+			})
 		}
-
-		// Entity buffer
-		buf := make([]*tokenize.Token, 0, len(toks))
-
-		// Entity iterator
-		n, s := iter.Pull2(slices.All(toks))
-		// Text iterator
-		m, t := iter.Pull2(slices.All(tokens))
-		for {
-			// If no entity is left, cancel.
-			j, v, ok := n()
-			if !ok {
-				s()
-				t()
-				break
-			}
-			// If no text is left, cancel.
-			_, w, ok := m()
-			if !ok {
-				s()
-				t()
-				break
-			}
-
-			if w.Text.Content != v.Text.Content {
-				i = 0
-				found = false
-				s()
-				t()
-				// Continue with next entity.
-				break
-			}
-
-			buf = append(buf, w)
-			i = j
+	} else {
+		for node, n := range aliases.all(keys[i:]) {
+			candidates = append(candidates, candidate{
+				id:     node.id,
+				alias:  node.alias,
+				tokens: node.tokens,
+				n:      n,
+			})
 		}
-
-		if found {
-			return entity, buf, i
+	}
+	for _, entity := range source.entities {
+		for _, p := range entity.Patterns {
+			if n := p.match(tokens[i:end], source.match); n > 0 {
+				candidates = append(candidates, candidate{
+					id:      entity.ID,
+					alias:   p.String(),
+					pattern: p,
+					n:       n,
+				})
+			}
 		}
 	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.n, a.n)
+	})
 
-	return "", nil, -1
+	return candidates
 }
@@ -3,6 +3,7 @@ package entitydebs
 import (
 	"bytes"
 	"context"
+	"errors"
 	"slices"
 	"strings"
 	"testing"
@@ -145,6 +146,7 @@ func Test_sourceFrames(t *testing.T) {
 	type (
 		want struct {
 			entities map[string][]tokenize.Token
+			ids      map[string]string
 			frames   []frame
 		}
 		test struct {
@@ -179,8 +181,8 @@ func Test_sourceFrames(t *testing.T) {
 		)
 		tests = append(tests, test{
 			source: source{
-				texts:  texts,
-				entity: entity,
+				texts:    texts,
+				entities: []Entity{{Aliases: entity}},
 			},
 			feats: tokenize.FeatureSyntax,
 			want: want{
@@ -190,6 +192,7 @@ func Test_sourceFrames(t *testing.T) {
 						*tokens[6],
 					},
 				},
+				ids: map[string]string{"New York": ""},
 				frames: []frame{
 					{
 						sentences: []*tokenize.Sentence{
@@ -206,6 +209,9 @@ func Test_sourceFrames(t *testing.T) {
 								tokens[6],
 							},
 						},
+						matches: map[int]match{
+							5: {alias: "New York"},
+						},
 					},
 				},
 			},
@@ -231,14 +237,15 @@ func Test_sourceFrames(t *testing.T) {
 		)
 		tests = append(tests, test{
 			source: source{
-				texts:  texts,
-				entity: entity,
+				texts:    texts,
+				entities: []Entity{{Aliases: entity}},
 			},
 			feats: tokenize.FeatureSyntax,
 			want: want{
 				entities: map[string][]tokenize.Token{
 					entity[0]: {*tokens[1]},
 				},
+				ids: map[string]string{"Punchinello": ""},
 				frames: []frame{
 					{
 						sentences: []*tokenize.Sentence{
@@ -252,6 +259,9 @@ func Test_sourceFrames(t *testing.T) {
 						entities: map[int][]*tokenize.Token{
 							1: {tokens[1]},
 						},
+						matches: map[int]match{
+							1: {alias: "Punchinello"},
+						},
 					},
 				},
 			},
@@ -292,14 +302,15 @@ func Test_sourceFrames(t *testing.T) {
 		)
 		tests = append(tests, test{
 			source: source{
-				texts:  texts,
-				entity: entity,
+				texts:    texts,
+				entities: []Entity{{Aliases: entity}},
 			},
 			feats: tokenize.FeatureSyntax,
 			want: want{
 				entities: map[string][]tokenize.Token{
 					entity[0]: {*tokens[0]},
 				},
+				ids: map[string]string{"Punchinello": ""},
 				frames: []frame{
 					{
 						sentences: []*tokenize.Sentence{
@@ -310,6 +321,9 @@ func Test_sourceFrames(t *testing.T) {
 						entities: map[int][]*tokenize.Token{
 							0: {tokens[0]},
 						},
+						matches: map[int]match{
+							0: {alias: "Punchinello"},
+						},
 					},
 				},
 			},
@@ -333,8 +347,8 @@ func Test_sourceFrames(t *testing.T) {
 		)
 		tests = append(tests, test{
 			source: source{
-				texts:  texts,
-				entity: entity,
+				texts:    texts,
+				entities: []Entity{{Aliases: entity}},
 			},
 			feats: tokenize.FeatureSyntax,
 			want: want{
@@ -342,6 +356,7 @@ func Test_sourceFrames(t *testing.T) {
 					entity[0]: {*tokens[0]},
 					entity[1]: {*tokens[2]},
 				},
+				ids: map[string]string{"V": "", "Valhalla": ""},
 				frames: []frame{
 					{
 						sentences: []*tokenize.Sentence{
@@ -356,6 +371,10 @@ func Test_sourceFrames(t *testing.T) {
 							0: {tokens[0]},
 							2: {tokens[2]},
 						},
+						matches: map[int]match{
+							0: {alias: "V"},
+							2: {alias: "Valhalla"},
+						},
 					},
 				},
 			},
@@ -378,12 +397,13 @@ func Test_sourceFrames(t *testing.T) {
 		)
 		tests = append(tests, test{
 			source: source{
-				texts:  texts,
-				entity: entity,
+				texts:    texts,
+				entities: []Entity{{Aliases: entity}},
 			},
 			feats: tokenize.FeatureSyntax | tokenize.FeatureSentiment,
 			want: want{
 				entities: map[string][]tokenize.Token{},
+				ids:      map[string]string{},
 				frames: []frame{
 					{
 						sentences: []*tokenize.Sentence{
@@ -425,8 +445,8 @@ func Test_sourceFrames(t *testing.T) {
 		)
 		tests = append(tests, test{
 			source: source{
-				texts:  texts,
-				entity: entity,
+				texts:    texts,
+				entities: []Entity{{Aliases: entity}},
 			},
 			feats: tokenize.FeatureSyntax,
 			normalizer: []Normalizer{
@@ -439,6 +459,7 @@ func Test_sourceFrames(t *testing.T) {
 						*tokens[6],
 					},
 				},
+				ids: map[string]string{"New York": ""},
 				frames: []frame{
 					{
 						sentences: []*tokenize.Sentence{
@@ -451,6 +472,9 @@ func Test_sourceFrames(t *testing.T) {
 								tokens[6],
 							},
 						},
+						matches: map[int]match{
+							5: {alias: "New York"},
+						},
 					},
 				},
 			},
@@ -482,6 +506,7 @@ func Test_sourceFrames(t *testing.T) {
 			got, want := testhelper.MarshalJSON(t, frames), testhelper.MarshalJSON(t, Frames{
 				frames:   test.want.frames,
 				entities: test.want.entities,
+				ids:      test.want.ids,
 			})
 			if !bytes.Equal(got, want) {
 				t.Errorf("source.Frames(%s, %s, %d) = %s, _, want %s ",
@@ -520,3 +545,124 @@ func normalize(t *testing.T, frames []frame, tokens []*tokenize.Token, normalize
 		}
 	}
 }
+
+func Test_sourceFramesMultiSource(t *testing.T) {
+	t.Parallel()
+
+	src := NewMultiSource([]Entity{
+		{ID: "v", Aliases: []string{"V"}},
+		{ID: "valhalla", Aliases: []string{"Valhalla"}},
+	}, []string{"V for Valhalla."})
+	frames, err := src.Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	want := map[int]match{
+		0: {id: "v", alias: "V"},
+		2: {id: "valhalla", alias: "Valhalla"},
+	}
+	if diff := cmp.Diff(want, frames.frames[0].matches, cmp.AllowUnexported(match{})); diff != "" {
+		t.Errorf("source.Frames().frames[0].matches mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"V": "v", "Valhalla": "valhalla"}, frames.ids); diff != "" {
+		t.Errorf("source.Frames().ids mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// failTokenizer fails to tokenize text fail. Other texts are tokenized by
+// next, or by mockTokenizer if next is nil.
+type failTokenizer struct {
+	fail string
+	err  error
+	next tokenize.Tokenizer
+}
+
+func (tokenizer failTokenizer) Tokenize(
+	ctx context.Context,
+	text string,
+	feats tokenize.Features,
+) (
+	tokenize.Analysis,
+	error,
+) {
+	if text == tokenizer.fail {
+		return tokenize.Analysis{}, tokenizer.err
+	}
+	if tokenizer.next != nil {
+		return tokenizer.next.Tokenize(ctx, text, feats)
+	}
+
+	return mockTokenizer{}.Tokenize(ctx, text, feats)
+}
+
+type tokenizerFunc func(context.Context, string, tokenize.Features) (tokenize.Analysis, error)
+
+func (fn tokenizerFunc) Tokenize(
+	ctx context.Context,
+	text string,
+	feats tokenize.Features,
+) (
+	tokenize.Analysis,
+	error,
+) {
+	return fn(ctx, text, feats)
+}
+
+func Test_sourceFramesConcurrency(t *testing.T) {
+	t.Parallel()
+
+	texts := make([]string, 0)
+	for i := range 32 {
+		texts = append(texts, strings.Repeat("Valhalla is far. ", i%5+1))
+	}
+
+	want, err := NewSource([]string{"Valhalla"}, slices.Clone(texts)).Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+	got, err := NewSource([]string{"Valhalla"}, slices.Clone(texts), WithConcurrency(8)).Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax)
+	if err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+	if diff := cmp.Diff(want.frames, got.frames, cmp.AllowUnexported(frame{}, match{})); diff != "" {
+		t.Errorf("source.Frames() frames mismatch (-want +got):\n%s", diff)
+	}
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			want = errors.New("tokenizer failed")
+			// Three fails after One and Two finished, Four blocks until
+			// cancelled.
+			finished  = make(chan struct{}, 2)
+			tokenizer = tokenizerFunc(func(ctx context.Context, text string, feats tokenize.Features) (tokenize.Analysis, error) {
+				switch text {
+				case "Three.":
+					<-finished
+					<-finished
+					return tokenize.Analysis{}, want
+				case "Four.":
+					<-ctx.Done()
+					return tokenize.Analysis{}, ctx.Err()
+				case "One.", "Two.":
+					defer func() { finished <- struct{}{} }()
+				}
+				return mockTokenizer{}.Tokenize(ctx, text, feats)
+			})
+		)
+		src := NewSource([]string{"Valhalla"}, []string{"One.", "Two.", "Three.", "Four."}, WithConcurrency(4))
+		frames, err := src.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax)
+		if !errors.Is(err, want) {
+			t.Fatalf("source.Frames() = _, %v, want %s", err, want)
+		}
+		var textErr *TextError
+		if !errors.As(err, &textErr) || textErr.Index != 2 {
+			t.Errorf("source.Frames() = _, %v, want text error of index 2", err)
+		}
+		if got := len(frames.frames); got != 2 {
+			t.Errorf("len(source.Frames().frames) = %d, want 2", got)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package entitydebs
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+// prefilterSep separates the selected sentence groups of a prefiltered text.
+const prefilterSep = "\n\n"
+
+// WithPrefilter tokenizes only sentences whose raw text contains an alias or
+// an annotation, and window sentences before and after them. Texts without
+// alias are not tokenized at all. Aliases are found under the matching mode,
+// lemmas aren't considered. Patterns only match within the selected sentences.
+//
+// Offsets are rebased to the original text. The document sentiment only
+// reflects the selected sentences.
+func WithPrefilter(window int) Option {
+	return func(source *source) {
+		source.prefilter = true
+		source.window = max(window, 0)
+	}
+}
+
+// tokenize tokenizes text, or only its selected sentences if prefiltering is
+// enabled.
+func (source source) tokenize(
+	ctx context.Context,
+	tokenizer tokenize.Tokenizer,
+	text string,
+	annotations []spanMatch,
+	feats tokenize.Features,
+) (
+	tokenize.Analysis,
+	error,
+) {
+	if !source.prefilter {
+		return tokenizer.Tokenize(ctx, text, feats)
+	}
+
+	groups := source.selectSentences(text, annotations)
+	if len(groups) == 0 {
+		return tokenize.Analysis{}, nil
+	}
+
+	// Join groups to tokenize them in a single request, and remember where
+	// each group begins within the joined text.
+	var (
+		sb     strings.Builder
+		begins = make([]int, len(groups))
+	)
+	for i, group := range groups {
+		if i > 0 {
+			sb.WriteString(prefilterSep)
+		}
+		begins[i] = sb.Len()
+		sb.WriteString(text[group.begin:group.end])
+	}
+	analysis, err := tokenizer.Tokenize(ctx, sb.String(), feats)
+	if err != nil {
+		return analysis, err
+	}
+
+	// Rebase offsets to the original text.
+	rebase := func(span *tokenize.TextSpan) {
+		if span == nil {
+			return
+		}
+		offset := int(span.BeginOffset)
+		i := sort.SearchInts(begins, offset+1) - 1
+		if i < 0 {
+			return
+		}
+		span.BeginOffset = int32(offset - begins[i] + groups[i].begin)
+	}
+	for _, sentence := range analysis.Sentences {
+		rebase(sentence.Text)
+	}
+	for _, token := range analysis.Tokens {
+		rebase(token.Text)
+	}
+
+	return analysis, nil
+}
+
+// selectSentences returns the spans of consecutive sentences of text that
+// contain an alias, or an annotation if source is annotated, or are within the
+// window of such a sentence.
+func (source source) selectSentences(text string, annotations []spanMatch) []span {
+	var occurrences []occurrence
+	if source.annotations != nil {
+		for _, annotation := range annotations {
+			occurrences = append(occurrences, occurrence{span: annotation.span})
+		}
+	} else {
+		aliases := make([]string, 0)
+		for _, entity := range source.entities {
+			aliases = append(aliases, entity.Aliases...)
+		}
+		occurrences = scan(text, aliases, source.match, false)
+	}
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	// Select sentences with occurrences, including their window.
+	var (
+		sentences = split(text)
+		selected  = make([]bool, len(sentences))
+		k         = 0
+	)
+	for i, sentence := range sentences {
+		for k < len(occurrences) && occurrences[k].begin < sentence.end {
+			if occurrences[k].end > sentence.begin {
+				for j := max(i-source.window, 0); j <= min(i+source.window, len(sentences)-1); j++ {
+					selected[j] = true
+				}
+			}
+			k++
+		}
+	}
+
+	// Group consecutive selected sentences.
+	groups := make([]span, 0)
+	for i, sentence := range sentences {
+		if !selected[i] {
+			continue
+		}
+		if i > 0 && selected[i-1] {
+			groups[len(groups)-1].end = sentence.end
+			continue
+		}
+		groups = append(groups, sentence)
+	}
+
+	return groups
+}
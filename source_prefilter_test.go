@@ -0,0 +1,254 @@
+package entitydebs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+// offsetTokenizer splits texts into words and punctuation tokens with byte
+// offsets, and records all tokenized texts.
+type offsetTokenizer struct {
+	mu    *sync.Mutex
+	texts *[]string
+}
+
+func newOffsetTokenizer() offsetTokenizer {
+	return offsetTokenizer{mu: &sync.Mutex{}, texts: new([]string)}
+}
+
+func (tokenizer offsetTokenizer) Tokenize(
+	ctx context.Context,
+	text string,
+	feats tokenize.Features,
+) (
+	tokenize.Analysis,
+	error,
+) {
+	tokenizer.mu.Lock()
+	*tokenizer.texts = append(*tokenizer.texts, text)
+	tokenizer.mu.Unlock()
+
+	var (
+		analysis tokenize.Analysis
+		begin    = -1
+		sentence = -1
+	)
+	emit := func(end int) {
+		if sentence < 0 {
+			sentence = begin
+		}
+		analysis.Tokens = append(analysis.Tokens, &tokenize.Token{
+			Text: &tokenize.TextSpan{Content: text[begin:end], BeginOffset: int32(begin)},
+		})
+		begin = -1
+	}
+	for i, r := range text {
+		word := unicode.In(r, unicode.Letter, unicode.Number)
+		if begin >= 0 && !word {
+			emit(i)
+		}
+		switch {
+		case word:
+			if begin < 0 {
+				begin = i
+			}
+		case !unicode.IsSpace(r):
+			begin = i
+			emit(i + utf8.RuneLen(r))
+			if unicode.Is(unicode.Sentence_Terminal, r) {
+				analysis.Sentences = append(analysis.Sentences, &tokenize.Sentence{
+					Text: &tokenize.TextSpan{Content: text[sentence : i+1], BeginOffset: int32(sentence)},
+				})
+				sentence = -1
+			}
+		}
+	}
+	if begin >= 0 {
+		emit(len(text))
+	}
+	if sentence >= 0 {
+		analysis.Sentences = append(analysis.Sentences, &tokenize.Sentence{
+			Text: &tokenize.TextSpan{Content: text[sentence:], BeginOffset: int32(sentence)},
+		})
+	}
+
+	return analysis, nil
+}
+
+func Test_split(t *testing.T) {
+	t.Parallel()
+
+	text := " One. Two? Three\nFour 1.5 five "
+	want := []string{"One.", "Two?", "Three", "Four 1.5 five"}
+
+	got := make([]string, 0)
+	for _, s := range split(text) {
+		got = append(got, text[s.begin:s.end])
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("split() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_scan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		text    string
+		aliases []string
+		mode    Match
+		bounded bool
+		want    []string
+	}{
+		{
+			name:    "exact",
+			text:    "The United States and the united states.",
+			aliases: []string{"United States"},
+			want:    []string{"United States"},
+		},
+		{
+			name:    "fold",
+			text:    "The United States and the UNITED STATES.",
+			aliases: []string{"united states"},
+			mode:    MatchFold,
+			want:    []string{"United States", "UNITED STATES"},
+		},
+		{
+			name:    "longest",
+			text:    "The U.S. Army and the U.S. moved.",
+			aliases: []string{"U.S.", "U.S. Army"},
+			want:    []string{"U.S. Army", "U.S."},
+		},
+		{
+			name:    "unbounded",
+			text:    "Americans love America.",
+			aliases: []string{"America"},
+			want:    []string{"America", "America"},
+		},
+		{
+			name:    "bounded",
+			text:    "Americans love America.",
+			aliases: []string{"America"},
+			bounded: true,
+			want:    []string{"America"},
+		},
+		{
+			name:    "nfkc decomposed text",
+			text:    "Le Cafe\u0301 et le café.",
+			aliases: []string{"Café"},
+			mode:    MatchNFKC | MatchFold,
+			bounded: true,
+			want:    []string{"Cafe\u0301", "café"},
+		},
+		{
+			name:    "nfkc decomposed alias",
+			text:    "Le Café et le Cafe.",
+			aliases: []string{"Cafe\u0301"},
+			mode:    MatchNFKC,
+			want:    []string{"Café"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := make([]string, 0)
+			for _, o := range scan(tt.text, tt.aliases, tt.mode, tt.bounded) {
+				got = append(got, tt.text[o.begin:o.end])
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("scan() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_sourceFramesPrefilterNFKC(t *testing.T) {
+	t.Parallel()
+
+	tokenizer := newOffsetTokenizer()
+	src := NewSource([]string{"Café"}, []string{"Il pleut. Le Cafe\u0301 ouvre."}, WithMatch(MatchNFKC), WithPrefilter(0))
+	if _, err := src.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax); err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	// Sentences with decomposed aliases are tokenized.
+	if diff := cmp.Diff([]string{"Le Cafe\u0301 ouvre."}, (*tokenizer.texts)[1:]); diff != "" {
+		t.Errorf("tokenized texts mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_sourceFramesPrefilter(t *testing.T) {
+	t.Parallel()
+
+	texts := []string{
+		"One is here. Two is here. The US acts. Four is here. Five is here. Six and the US.",
+		"Nothing to see.",
+	}
+
+	tests := []struct {
+		name   string
+		window int
+		want   []string
+	}{
+		{
+			name:   "no window",
+			window: 0,
+			want:   []string{"The US acts." + prefilterSep + "Six and the US."},
+		},
+		{
+			name:   "window",
+			window: 1,
+			// Adjacent windows are merged.
+			want: []string{"Two is here. The US acts. Four is here. Five is here. Six and the US."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tokenizer := newOffsetTokenizer()
+			src := NewSource([]string{"US"}, append([]string(nil), texts...), WithPrefilter(tt.window))
+			frames, err := src.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax)
+			if err != nil {
+				t.Fatalf("source.Frames() = _, %s, want nil", err)
+			}
+
+			// The first call tokenizes the alias.
+			if diff := cmp.Diff(tt.want, (*tokenizer.texts)[1:]); diff != "" {
+				t.Errorf("tokenized texts mismatch (-want +got):\n%s", diff)
+			}
+			if len(frames.frames) != len(texts) {
+				t.Fatalf("len(source.Frames().frames) = %d, want %d", len(frames.frames), len(texts))
+			}
+			if len(frames.frames[1].tokens) != 0 {
+				t.Errorf("len(source.Frames().frames[1].tokens) = %d, want 0", len(frames.frames[1].tokens))
+			}
+
+			// Offsets are rebased to the original text.
+			frame := frames.frames[0]
+			for _, token := range frame.tokens {
+				begin := int(token.Text.BeginOffset)
+				if got := texts[0][begin : begin+len(token.Text.Content)]; got != token.Text.Content {
+					t.Errorf("token %q at offset %d = %q", token.Text.Content, begin, got)
+				}
+			}
+			for _, sentence := range frame.sentences {
+				begin := int(sentence.Text.BeginOffset)
+				if got := texts[0][begin : begin+len(sentence.Text.Content)]; got != sentence.Text.Content {
+					t.Errorf("sentence %q at offset %d = %q", sentence.Text.Content, begin, got)
+				}
+			}
+			if len(frame.entities) != 2 {
+				t.Errorf("len(source.Frames().frames[0].entities) = %d, want 2", len(frame.entities))
+			}
+		})
+	}
+}
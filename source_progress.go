@@ -0,0 +1,113 @@
+package entitydebs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+type (
+	// Progress reports the progress of a [Frames] computation.
+	Progress struct {
+		// Texts is the number of finished texts.
+		Texts int
+		// Failed is the number of skipped texts, see [ErrorPolicySkip].
+		// Texts and Failed add up to Total once all texts are processed.
+		Failed int
+		// Total is the number of texts, or zero if unknown, e.g., for streams.
+		Total int
+		// Tokens is the number of tokens of all finished texts.
+		Tokens int
+		// Mentions is the number of entity mentions of all finished texts.
+		Mentions int
+		// Retries is the number of retried tokenizer requests, e.g., after
+		// exceeding a rate limit. Tokenizers report retries with
+		// [tokenize.Retry].
+		Retries int
+		// Elapsed is the time since the computation started.
+		Elapsed time.Duration
+	}
+
+	// progress tracks the progress of a computation.
+	progress struct {
+		mu       sync.Mutex
+		fn       func(Progress)
+		start    time.Time
+		progress Progress
+	}
+)
+
+// WithProgress calls fn after every finished text and every retried tokenizer
+// request. Calls are sequential, even if texts are tokenized concurrently, so
+// fn should return quickly.
+func WithProgress(fn func(Progress)) Option {
+	return func(source *source) {
+		source.progress = fn
+	}
+}
+
+// newProgress returns a progress tracker of total texts, or nil if no
+// progress function is set.
+func (source source) newProgress(total int) *progress {
+	if source.progress == nil {
+		return nil
+	}
+
+	return &progress{
+		fn:       source.progress,
+		start:    time.Now(),
+		progress: Progress{Total: total},
+	}
+}
+
+// context returns a copy of ctx that reports retries to p.
+func (p *progress) context(ctx context.Context) context.Context {
+	if p == nil {
+		return ctx
+	}
+
+	return tokenize.WithRetryFunc(ctx, p.retry)
+}
+
+// text reports the finished frame f.
+func (p *progress) text(f frame) {
+	if p == nil {
+		return
+	}
+
+	p.report(func(progress *Progress) {
+		progress.Texts++
+		progress.Tokens += len(f.tokens)
+		progress.Mentions += len(f.matches)
+	})
+}
+
+// fail reports a skipped text.
+func (p *progress) fail() {
+	if p == nil {
+		return
+	}
+
+	p.report(func(progress *Progress) {
+		progress.Failed++
+	})
+}
+
+// retry reports a retried tokenizer request.
+func (p *progress) retry() {
+	p.report(func(progress *Progress) {
+		progress.Retries++
+	})
+}
+
+// report updates the progress with fn and calls the progress function.
+func (p *progress) report(fn func(*Progress)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fn(&p.progress)
+	p.progress.Elapsed = time.Since(p.start)
+	p.fn(p.progress)
+}
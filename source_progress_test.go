@@ -0,0 +1,71 @@
+package entitydebs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+// retryTokenizer retries every request once.
+type retryTokenizer struct{}
+
+func (retryTokenizer) Tokenize(ctx context.Context, text string, feats tokenize.Features) (tokenize.Analysis, error) {
+	tokenize.Retry(ctx)
+
+	return mockTokenizer{}.Tokenize(ctx, text, feats)
+}
+
+func Test_sourceFramesProgress(t *testing.T) {
+	t.Parallel()
+
+	var (
+		texts = []string{"Valhalla is far.", "Far far away.", "Valhalla and Valhalla."}
+		got   = make([]Progress, 0)
+	)
+	src := NewSource([]string{"Valhalla"}, texts, WithConcurrency(2), WithProgress(func(progress Progress) {
+		got = append(got, progress)
+	}))
+	if _, err := src.Frames(t.Context(), retryTokenizer{}, tokenize.FeatureSyntax); err != nil {
+		t.Fatalf("source.Frames() = _, %s, want nil", err)
+	}
+
+	// One retry for the alias and one retry and text report for each text.
+	if len(got) != 7 {
+		t.Fatalf("len(progress) = %d, want 7", len(got))
+	}
+	want := Progress{
+		Texts:    3,
+		Total:    3,
+		Tokens:   4 + 4 + 4,
+		Mentions: 1 + 0 + 2,
+		Retries:  4,
+	}
+	if diff := cmp.Diff(want, got[len(got)-1], cmpopts.IgnoreFields(Progress{}, "Elapsed")); diff != "" {
+		t.Errorf("Progress mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_sourceFramesProgressSkip(t *testing.T) {
+	t.Parallel()
+
+	var (
+		texts = []string{"One.", "Two.", "Three."}
+		last  Progress
+	)
+	src := NewSource([]string{"Valhalla"}, texts, WithErrorPolicy(ErrorPolicySkip), WithProgress(func(progress Progress) {
+		last = progress
+	}))
+	tokenizer := failTokenizer{fail: "Two.", err: errors.New("tokenizer failed")}
+	if _, err := src.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax); err == nil {
+		t.Fatal("source.Frames() = _, nil, want error")
+	}
+
+	want := Progress{Texts: 2, Failed: 1, Total: 3, Tokens: 2 + 2}
+	if diff := cmp.Diff(want, last, cmpopts.IgnoreFields(Progress{}, "Elapsed")); diff != "" {
+		t.Errorf("Progress mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,102 @@
+package entitydebs
+
+import (
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+// spanMatch is an entity occurrence within a raw text.
+type spanMatch struct {
+	span
+	id, alias string
+	// n is the number of tokens the occurrence overlaps.
+	n int
+}
+
+// WithSpans matches aliases on character spans of the raw text instead of
+// comparing separately tokenized aliases with the text tokens. Each occurrence
+// is mapped onto all tokens it overlaps, so aliases such as "U.S." match
+// regardless of how the tokenizer splits them in context. Aliases aren't
+// tokenized, which saves a tokenizer request per alias.
+//
+// Occurrences must begin and end at word boundaries, lemmas aren't considered.
+// The tokenizer must provide byte offsets.
+func WithSpans() Option {
+	return func(source *source) {
+		source.spans = true
+	}
+}
+
+// matchSpans returns the alias occurrences of text mapped onto tokens, keyed
+// by the index of their first token.
+func (source source) matchSpans(text string, tokens []*tokenize.Token) map[int]spanMatch {
+	var (
+		aliases = make([]string, 0)
+		ids     = make([]string, 0)
+	)
+	for _, entity := range source.entities {
+		for _, alias := range entity.Aliases {
+			aliases = append(aliases, alias)
+			ids = append(ids, entity.ID)
+		}
+	}
+
+	occurrences := scan(text, aliases, source.match, true)
+	matches := make([]spanMatch, 0, len(occurrences))
+	for _, o := range occurrences {
+		matches = append(matches, spanMatch{
+			span:  o.span,
+			id:    ids[o.alias],
+			alias: aliases[o.alias],
+		})
+	}
+
+	return mapSpans(matches, tokens)
+}
+
+// mapSpans maps matches onto the tokens they overlap, keyed by the index of
+// their first token. Matches must be ordered and must not overlap. Matches
+// overlapping no or already mapped tokens are skipped.
+func mapSpans(matches []spanMatch, tokens []*tokenize.Token) map[int]spanMatch {
+	var (
+		mapped = make(map[int]spanMatch)
+		i      = 0
+	)
+	for _, m := range matches {
+		// Skip tokens ending before the match.
+		for i < len(tokens) && tokenEnd(tokens[i]) <= m.begin {
+			i++
+		}
+		n := 0
+		for i+n < len(tokens) && tokenBegin(tokens[i+n]) < m.end {
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+
+		m.n = n
+		mapped[i] = m
+		// Tokens belong to a single match.
+		i += n
+	}
+
+	return mapped
+}
+
+// tokenBegin returns the byte offset of token.
+func tokenBegin(token *tokenize.Token) int {
+	if token.Text == nil {
+		return -1
+	}
+
+	return int(token.Text.BeginOffset)
+}
+
+// tokenEnd returns the exclusive byte offset of the end of token.
+func tokenEnd(token *tokenize.Token) int {
+	if token.Text == nil {
+		return -1
+	}
+
+	return int(token.Text.BeginOffset) + len(token.Text.Content)
+}
@@ -0,0 +1,77 @@
+package entitydebs
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func Test_sourceFramesSpans(t *testing.T) {
+	t.Parallel()
+
+	text := "The U.S. acts. The u.s. army too. Thus not."
+
+	tests := []struct {
+		name    string
+		entity  Entity
+		matches map[int]match
+		reasons []Reason
+	}{
+		{
+			name:   "fold",
+			entity: Entity{ID: "us", Aliases: []string{"U.S."}},
+			matches: map[int]match{
+				1: {id: "us", alias: "U.S."},
+				8: {id: "us", alias: "U.S."},
+			},
+		},
+		{
+			name: "case sensitive",
+			entity: Entity{
+				ID:      "us",
+				Aliases: []string{"U.S."},
+				Rules:   map[string]Rule{"U.S.": {CaseSensitive: true}},
+			},
+			matches: map[int]match{
+				1: {id: "us", alias: "U.S."},
+			},
+			reasons: []Reason{ReasonCase},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tokenizer := newOffsetTokenizer()
+			src := NewMultiSource([]Entity{tt.entity}, []string{text}, WithMatch(MatchFold), WithSpans())
+			frames, err := src.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax)
+			if err != nil {
+				t.Fatalf("source.Frames() = _, %s, want nil", err)
+			}
+
+			// Aliases aren't tokenized.
+			if diff := cmp.Diff([]string{text}, *tokenizer.texts); diff != "" {
+				t.Errorf("tokenized texts mismatch (-want +got):\n%s", diff)
+			}
+
+			frame := frames.frames[0]
+			if diff := cmp.Diff(tt.matches, frame.matches, cmp.AllowUnexported(match{})); diff != "" {
+				t.Errorf("source.Frames().frames[0].matches mismatch (-want +got):\n%s", diff)
+			}
+			// "U.S." is split into four tokens.
+			if got := len(frame.entities[1]); got != 4 {
+				t.Errorf("len(source.Frames().frames[0].entities[1]) = %d, want 4", got)
+			}
+
+			reasons := make([]Reason, 0)
+			for rejection := range frames.Rejections() {
+				reasons = append(reasons, rejection.Reason)
+			}
+			if !slices.Equal(tt.reasons, reasons) {
+				t.Errorf("Frames.Rejections() reasons = %v, want %v", reasons, tt.reasons)
+			}
+		})
+	}
+}
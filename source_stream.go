@@ -0,0 +1,122 @@
+package entitydebs
+
+import (
+	"context"
+	"iter"
+	"strings"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+type (
+	// stream wraps entities and a sequence of texts, and yields a data
+	// [Frames] for every text.
+	stream struct {
+		source source
+		// texts yields every text with its metadata, if any.
+		texts iter.Seq2[string, map[string]string]
+	}
+)
+
+// NewStream returns a new stream, consisting of the entity, its aliases and a
+// sequence of texts. Unlike [NewSource], texts are consumed lazily, so corpora
+// larger than memory can be analyzed. Duplicate entities and surrounding white
+// spaces are removed.
+//
+// By convention, the first entity is the most well-known.
+func NewStream(entity []string, texts iter.Seq[string], opts ...Option) stream {
+	return stream{
+		source: NewSource(entity, nil, opts...),
+		texts: func(yield func(string, map[string]string) bool) {
+			for text := range texts {
+				if !yield(text, nil) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// NewMultiStream returns a new stream, consisting of multiple entities with
+// their aliases and a sequence of texts with their metadata, e.g., the speaker
+// or date. It's the streaming equivalent of [NewMultiSource]. Metadata is
+// carried into the frame of its text and takes precedence over
+// [WithMetadata].
+func NewMultiStream(
+	entities []Entity,
+	texts iter.Seq2[string, map[string]string],
+	opts ...Option,
+) stream {
+	return stream{
+		source: NewMultiSource(entities, nil, opts...),
+		texts:  texts,
+	}
+}
+
+// Frames tokenizes the entities of stream once and then yields a [Frames] for
+// every text, each containing a single data frame. Frames are not retained,
+// use [Aggregate] to accumulate results across texts or [NewFramesWriter] to
+// write them.
+//
+// Errors of texts are yielded as [*TextError]. Iteration stops after the
+// first error, unless failed texts are skipped, see [WithErrorPolicy].
+//
+// [Normalizer] can be used to to reduce redundancy and improve data integrity.
+// Normalizers are not applied to entity tokens.
+func (stream stream) Frames(
+	ctx context.Context,
+	tokenizer tokenize.Tokenizer,
+	feats tokenize.Features,
+	normalizer ...Normalizer,
+) iter.Seq2[Frames, error] {
+	return func(yield func(Frames, error) bool) {
+		progress := stream.source.newProgress(0)
+		ctx := progress.context(ctx)
+
+		// Tokenize entities.
+		entities, ids, err := stream.source.tokenizeEntities(ctx, tokenizer)
+		if err != nil {
+			yield(Frames{}, err)
+			return
+		}
+		aliases := newTrie(stream.source.entities, entities, stream.source.match)
+
+		i := -1
+		for text, metadata := range stream.texts {
+			i++
+			select {
+			case <-ctx.Done():
+				yield(Frames{}, ctx.Err())
+				return
+			default:
+			}
+
+			text = strings.TrimSpace(text)
+			f, err := stream.source.frame(ctx, tokenizer, text, nil, aliases, feats, normalizer...)
+			if err != nil {
+				err = &TextError{Index: i, Err: err}
+				if !yield(Frames{}, err) || stream.source.policy != ErrorPolicySkip || ctx.Err() != nil {
+					return
+				}
+				progress.fail()
+				continue
+			}
+			f.index = i
+			f.metadata = metadata
+			if f.metadata == nil {
+				f.metadata = stream.source.metadataOf(i)
+			}
+
+			progress.text(f)
+
+			frames := Frames{
+				frames:   []frame{f},
+				entities: entities,
+				ids:      ids,
+			}
+			if !yield(frames, nil) {
+				return
+			}
+		}
+	}
+}
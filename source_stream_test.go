@@ -0,0 +1,109 @@
+package entitydebs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func Test_streamFrames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("yields frame per text", func(t *testing.T) {
+		t.Parallel()
+
+		texts := []string{
+			"Everything ripped apart in a New York minute.",
+			" Pissing Punchinello off was a dangerous game ",
+			"Welcome to New York.",
+		}
+		stream := NewStream([]string{"New York"}, slices.Values(texts))
+
+		var (
+			entities = make([]int, 0)
+			tokens   = make([]int, 0)
+		)
+		for frames, err := range stream.Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax) {
+			if err != nil {
+				t.Fatalf("stream.Frames() = _, %s, want nil", err)
+			}
+			if n := len(frames.frames); n != 1 {
+				t.Fatalf("len(stream.Frames().frames) = %d, want 1", n)
+			}
+
+			frame := frames.frames[0]
+			entities = append(entities, len(frame.entities))
+			tokens = append(tokens, len(frame.tokens))
+		}
+
+		if want := []int{1, 0, 1}; !slices.Equal(entities, want) {
+			t.Errorf("stream.Frames() entities = %v, want %v", entities, want)
+		}
+		if want := []int{9, 7, 5}; !slices.Equal(tokens, want) {
+			t.Errorf("stream.Frames() tokens = %v, want %v", tokens, want)
+		}
+	})
+	t.Run("stops on error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			want      = errors.New("tokenizer failed")
+			tokenizer = failTokenizer{fail: "b", err: want, next: mockTokenizer{}}
+			stream    = NewStream([]string{"New York"}, slices.Values([]string{"a", "b", "c"}))
+			errs      = make([]error, 0)
+		)
+		for _, err := range stream.Frames(t.Context(), tokenizer, tokenize.FeatureSyntax) {
+			errs = append(errs, err)
+		}
+		if len(errs) != 2 || errs[0] != nil {
+			t.Fatalf("stream.Frames() errors = %v, want [<nil> %s]", errs, want)
+		}
+		if !errors.Is(errs[1], want) {
+			t.Errorf("stream.Frames() = _, %v, want %v", errs[1], want)
+		}
+		var textErr *TextError
+		if !errors.As(errs[1], &textErr) || textErr.Index != 1 {
+			t.Errorf("stream.Frames() = _, %v, want *TextError of text 1", errs[1])
+		}
+	})
+}
+
+func Test_multiStreamFrames(t *testing.T) {
+	t.Parallel()
+
+	texts := func(yield func(string, map[string]string) bool) {
+		_ = yield("The US and Germany met.", map[string]string{"speaker": "a"}) &&
+			yield("Germany left.", map[string]string{"speaker": "b"})
+	}
+	stream := NewMultiStream([]Entity{
+		{ID: "us", Aliases: []string{"US"}},
+		{ID: "de", Aliases: []string{"Germany"}},
+	}, texts)
+
+	var (
+		matches  = make([]map[int]match, 0)
+		speakers = make([]string, 0)
+	)
+	for frames, err := range stream.Frames(t.Context(), newOffsetTokenizer(), tokenize.FeatureSyntax) {
+		if err != nil {
+			t.Fatalf("stream.Frames() = _, %s, want nil", err)
+		}
+		frame := frames.frames[0]
+		matches = append(matches, frame.matches)
+		speakers = append(speakers, frame.metadata["speaker"])
+	}
+
+	want := []map[int]match{
+		{1: {id: "us", alias: "US"}, 3: {id: "de", alias: "Germany"}},
+		{0: {id: "de", alias: "Germany"}},
+	}
+	if diff := cmp.Diff(want, matches, cmp.AllowUnexported(match{})); diff != "" {
+		t.Errorf("stream.Frames() matches mismatch (-want +got):\n%s", diff)
+	}
+	if want := []string{"a", "b"}; !slices.Equal(speakers, want) {
+		t.Errorf("stream.Frames() speakers = %v, want %v", speakers, want)
+	}
+}
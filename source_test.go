@@ -0,0 +1,42 @@
+package entitydebs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewSource(t *testing.T) {
+	t.Parallel()
+
+	src := NewSource([]string{" United States", "US", "United States ", ""}, []string{" text "})
+	want := []Entity{
+		{ID: "United States", Aliases: []string{"United States", "US"}},
+	}
+	if diff := cmp.Diff(want, src.entities); diff != "" {
+		t.Errorf("NewSource().entities mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"text"}, src.texts); diff != "" {
+		t.Errorf("NewSource().texts mismatch (-want +got):\n%s", diff)
+	}
+
+	if src := NewSource(nil, nil); len(src.entities) != 0 {
+		t.Errorf("NewSource(nil, nil).entities = %v, want empty", src.entities)
+	}
+}
+
+func TestNewMultiSource(t *testing.T) {
+	t.Parallel()
+
+	src := NewMultiSource([]Entity{
+		{ID: "us", Aliases: []string{"United States", "US", "US"}},
+		{ID: "cn", Aliases: []string{"China ", "US", "PRC"}},
+	}, nil)
+	want := []Entity{
+		{ID: "us", Aliases: []string{"United States", "US"}},
+		{ID: "cn", Aliases: []string{"China", "PRC"}},
+	}
+	if diff := cmp.Diff(want, src.entities); diff != "" {
+		t.Errorf("NewMultiSource().entities mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -13,6 +13,8 @@ type Analysis struct {
 	Tokens []*Token
 	// Sentiment is the documents Sentiment.
 	Sentiment *Sentiment
+	// Language is the language of the text, e.g., detected by the tokenizer.
+	Language string
 }
 
 func (a Analysis) String() string {
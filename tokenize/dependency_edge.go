@@ -97,3 +97,7 @@ const (
 	DependencyEdgeLabelMes          = DependencyEdgeLabel(v1beta2.DependencyEdge_MES)
 	DependencyEdgeLabelNComp        = DependencyEdgeLabel(v1beta2.DependencyEdge_NCOMP)
 )
+
+func (label DependencyEdgeLabel) String() string {
+	return v1beta2.DependencyEdge_Label(label).String()
+}
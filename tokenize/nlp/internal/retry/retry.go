@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/googleapis/gax-go/v2/apierror"
+	"github.com/ndabAP/entitydebs/tokenize"
 	"google.golang.org/genproto/googleapis/api/error_reason"
 )
 
@@ -44,6 +45,8 @@ func Do(ctx context.Context, req func() error) error {
 				return err
 			}
 
+			tokenize.Retry(ctx)
+
 			// Exponentially back-off
 			time.Sleep(time.Second * time.Duration(delay))
 
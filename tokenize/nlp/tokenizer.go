@@ -3,6 +3,8 @@ package nlp
 import (
 	"context"
 
+	syntaxpb "cloud.google.com/go/language/apiv1beta2/languagepb"
+	annotatepb "cloud.google.com/go/language/apiv2/languagepb"
 	"github.com/ndabAP/entitydebs/tokenize"
 	"github.com/ndabAP/entitydebs/tokenize/nlp/language"
 	"github.com/ndabAP/entitydebs/tokenize/nlp/v1beta2"
@@ -36,12 +38,10 @@ func New(creds, lang string) tokenize.Tokenizer {
 
 // Tokenize implements the [tokenize.Tokenizer] interface.
 func (nlp nlp) Tokenize(ctx context.Context, text string, feats tokenize.Features) (tokenize.Analysis, error) {
-	var analysis tokenize.Analysis
-
+	// Responses of features that aren't requested stay nil.
 	var (
-		sentences []*tokenize.Sentence
-		tokens    []*tokenize.Token
-		sentiment = &tokenize.Sentiment{}
+		syntax    *syntaxpb.AnalyzeSyntaxResponse
+		annotated *annotatepb.AnnotateTextResponse
 	)
 
 	fns := make([]func() error, 0)
@@ -52,9 +52,58 @@ func (nlp nlp) Tokenize(ctx context.Context, text string, feats tokenize.Feature
 		if err != nil {
 			return err
 		}
+		syntax = res
+		return nil
+	}
+
+	// Analyse sentiment
+	annotatefn := func(feats v2.Features) func() error {
+		return func() error {
+			res, err := v2.New(nlp.creds, nlp.lang).Annotate(ctx, text, feats)
+			if err != nil {
+				return err
+			}
+			annotated = res
+			return nil
+		}
+	}
 
-		sentences = make([]*tokenize.Sentence, len(res.GetSentences()))
-		for i, s := range res.GetSentences() {
+	// Syntax
+	if feats&tokenize.FeatureSyntax != 0 {
+		fns = append(fns, syntaxfn)
+	}
+	var v2feats v2.Features
+	// Sentiment
+	if feats&tokenize.FeatureSentiment != 0 {
+		v2feats += v2.ExtractSentiment
+		fns = append(fns, annotatefn(v2feats))
+	}
+	// All features
+	if feats == tokenize.FeatureAll {
+		fns = []func() error{syntaxfn, annotatefn(v2feats)}
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, fn := range fns {
+		g.Go(fn)
+	}
+	if err := g.Wait(); err != nil {
+		return tokenize.Analysis{}, err
+	}
+
+	return analyze(syntax, annotated), nil
+}
+
+// analyze returns the analysis of the syntax and sentiment responses. Nil
+// responses aren't analyzed, so their fields stay unset.
+func analyze(syntax *syntaxpb.AnalyzeSyntaxResponse, annotated *annotatepb.AnnotateTextResponse) tokenize.Analysis {
+	var analysis tokenize.Analysis
+
+	if syntax != nil {
+		analysis.Language = syntax.GetLanguage()
+
+		analysis.Sentences = make([]*tokenize.Sentence, len(syntax.GetSentences()))
+		for i, s := range syntax.GetSentences() {
 			sentence := tokenize.Sentence{}
 			if s.Text != nil {
 				sentence.Text = &tokenize.TextSpan{
@@ -68,11 +117,11 @@ func (nlp nlp) Tokenize(ctx context.Context, text string, feats tokenize.Feature
 					Score:     s.Sentiment.Score,
 				}
 			}
-			sentences[i] = &sentence
+			analysis.Sentences[i] = &sentence
 		}
 
-		tokens = make([]*tokenize.Token, len(res.GetTokens()))
-		for i, t := range res.GetTokens() {
+		analysis.Tokens = make([]*tokenize.Token, len(syntax.GetTokens()))
+		for i, t := range syntax.GetTokens() {
 			token := tokenize.Token{}
 			if t.Text != nil {
 				token.Text = &tokenize.TextSpan{
@@ -103,54 +152,53 @@ func (nlp nlp) Tokenize(ctx context.Context, text string, feats tokenize.Feature
 				}
 			}
 			token.Lemma = t.Lemma
-			tokens[i] = &token
+			analysis.Tokens[i] = &token
 		}
-
-		return nil
 	}
 
-	// Analyse sentiment
-	annotatefn := func(feats v2.Features) func() error {
-		return func() error {
-			res, err := v2.New(nlp.creds, nlp.lang).Annotate(ctx, text, feats)
-			if err != nil {
-				return err
+	if annotated != nil {
+		if s := annotated.GetDocumentSentiment(); s != nil {
+			analysis.Sentiment = &tokenize.Sentiment{
+				Magnitude: s.Magnitude,
+				Score:     s.Score,
 			}
+		}
 
-			if s := res.GetDocumentSentiment(); s != nil {
-				sentiment.Magnitude = s.Magnitude
-				sentiment.Score = s.Score
+		sentences := make([]*tokenize.Sentence, len(annotated.GetSentences()))
+		for i, s := range annotated.GetSentences() {
+			sentence := tokenize.Sentence{}
+			if s.Text != nil {
+				sentence.Text = &tokenize.TextSpan{
+					Content:     s.Text.Content,
+					BeginOffset: s.Text.BeginOffset,
+				}
 			}
-			return nil
+			if s.Sentiment != nil {
+				sentence.Sentiment = &tokenize.Sentiment{
+					Magnitude: s.Sentiment.Magnitude,
+					Score:     s.Sentiment.Score,
+				}
+			}
+			sentences[i] = &sentence
 		}
-	}
-
-	// Syntax
-	if feats&tokenize.FeatureSyntax != 0 {
-		fns = append(fns, syntaxfn)
-	}
-	var v2feats v2.Features
-	// Sentiment
-	if feats&tokenize.FeatureSentiment != 0 {
-		v2feats += v2.ExtractSentiment
-		fns = append(fns, annotatefn(v2feats))
-	}
-	// All features
-	if feats == tokenize.FeatureAll {
-		fns = []func() error{syntaxfn, annotatefn(v2feats)}
-	}
 
-	g, ctx := errgroup.WithContext(ctx)
-	for _, fn := range fns {
-		g.Go(fn)
-	}
-	if err := g.Wait(); err != nil {
-		return analysis, err
+		// Syntax analysis doesn't return sentence sentiments, so they are
+		// taken from the annotated sentences with the same offset.
+		if syntax == nil {
+			analysis.Sentences = sentences
+		}
+		for _, sentence := range analysis.Sentences {
+			if sentence.Sentiment != nil || sentence.Text == nil {
+				continue
+			}
+			for _, s := range sentences {
+				if s.Text != nil && s.Text.BeginOffset == sentence.Text.BeginOffset {
+					sentence.Sentiment = s.Sentiment
+					break
+				}
+			}
+		}
 	}
 
-	analysis.Tokens = tokens
-	analysis.Sentences = sentences
-	analysis.Sentiment = sentiment
-
-	return analysis, nil
+	return analysis
 }
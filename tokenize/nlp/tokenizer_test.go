@@ -0,0 +1,92 @@
+package nlp
+
+import (
+	"testing"
+
+	syntaxpb "cloud.google.com/go/language/apiv1beta2/languagepb"
+	annotatepb "cloud.google.com/go/language/apiv2/languagepb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func Test_analyze(t *testing.T) {
+	t.Parallel()
+
+	var (
+		syntax = &syntaxpb.AnalyzeSyntaxResponse{
+			Sentences: []*syntaxpb.Sentence{
+				{Text: &syntaxpb.TextSpan{Content: "US wins.", BeginOffset: 0}},
+				{Text: &syntaxpb.TextSpan{Content: "UK loses.", BeginOffset: 9}},
+			},
+			Language: "en",
+		}
+		annotated = &annotatepb.AnnotateTextResponse{
+			DocumentSentiment: &annotatepb.Sentiment{Magnitude: 1.2, Score: 0.1},
+			Sentences: []*annotatepb.Sentence{
+				{
+					Text:      &annotatepb.TextSpan{Content: "US wins.", BeginOffset: 0},
+					Sentiment: &annotatepb.Sentiment{Magnitude: 0.8, Score: 0.8},
+				},
+				{
+					Text:      &annotatepb.TextSpan{Content: "UK loses.", BeginOffset: 9},
+					Sentiment: &annotatepb.Sentiment{Magnitude: 0.4, Score: -0.4},
+				},
+			},
+		}
+
+		wins  = &tokenize.TextSpan{Content: "US wins.", BeginOffset: 0}
+		loses = &tokenize.TextSpan{Content: "UK loses.", BeginOffset: 9}
+	)
+
+	tests := []struct {
+		name      string
+		syntax    *syntaxpb.AnalyzeSyntaxResponse
+		annotated *annotatepb.AnnotateTextResponse
+		want      tokenize.Analysis
+	}{
+		{
+			name:   "syntax",
+			syntax: syntax,
+			// Without sentiment analysis, there's no document sentiment.
+			want: tokenize.Analysis{
+				Sentences: []*tokenize.Sentence{{Text: wins}, {Text: loses}},
+				Tokens:    []*tokenize.Token{},
+				Language:  "en",
+			},
+		},
+		{
+			name:      "sentiment",
+			annotated: annotated,
+			want: tokenize.Analysis{
+				Sentences: []*tokenize.Sentence{
+					{Text: wins, Sentiment: &tokenize.Sentiment{Magnitude: 0.8, Score: 0.8}},
+					{Text: loses, Sentiment: &tokenize.Sentiment{Magnitude: 0.4, Score: -0.4}},
+				},
+				Sentiment: &tokenize.Sentiment{Magnitude: 1.2, Score: 0.1},
+			},
+		},
+		{
+			name:      "syntax and sentiment",
+			syntax:    syntax,
+			annotated: annotated,
+			want: tokenize.Analysis{
+				Sentences: []*tokenize.Sentence{
+					{Text: wins, Sentiment: &tokenize.Sentiment{Magnitude: 0.8, Score: 0.8}},
+					{Text: loses, Sentiment: &tokenize.Sentiment{Magnitude: 0.4, Score: -0.4}},
+				},
+				Tokens:    []*tokenize.Token{},
+				Sentiment: &tokenize.Sentiment{Magnitude: 1.2, Score: 0.1},
+				Language:  "en",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, analyze(tt.syntax, tt.annotated)); diff != "" {
+				t.Errorf("analyze() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
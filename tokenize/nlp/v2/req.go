@@ -54,8 +54,9 @@ func (v2 api) Annotate(ctx context.Context, text string, feats Features) (*langu
 		}
 
 		res, err = client.AnnotateText(ctx, &languagepb.AnnotateTextRequest{
-			Document: doc,
-			Features: f,
+			Document:     doc,
+			Features:     f,
+			EncodingType: languagepb.EncodingType_UTF8,
 		})
 
 		return err
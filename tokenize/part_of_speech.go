@@ -127,3 +127,7 @@ const (
 	PartOfSpeechVoiceCausative = PartOfSpeechVoice(v1beta2.PartOfSpeech_CAUSATIVE)
 	PartOfSpeechVoicePassive   = PartOfSpeechVoice(v1beta2.PartOfSpeech_PASSIVE)
 )
+
+func (tag PartOfSpeechTag) String() string {
+	return v1beta2.PartOfSpeech_Tag(tag).String()
+}
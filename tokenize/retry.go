@@ -0,0 +1,20 @@
+package tokenize
+
+import (
+	"context"
+)
+
+type retryKey struct{}
+
+// WithRetryFunc returns a copy of ctx that carries fn. Tokenizers report
+// retried requests, e.g., after exceeding a rate limit, to fn with [Retry].
+func WithRetryFunc(ctx context.Context, fn func()) context.Context {
+	return context.WithValue(ctx, retryKey{}, fn)
+}
+
+// Retry reports a retried request to the retry function of ctx, if any.
+func Retry(ctx context.Context) {
+	if fn, ok := ctx.Value(retryKey{}).(func()); ok && fn != nil {
+		fn()
+	}
+}
@@ -0,0 +1,85 @@
+package entitydebs
+
+import (
+	"iter"
+
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+// trie is a token trie of entity aliases. Edges are keyed by the token text
+// under the matching mode, so aliases sharing a prefix share nodes.
+type trie struct {
+	children map[string]*trie
+
+	// id, alias and tokens are set if an alias ends at this node.
+	id, alias string
+	tokens    []tokenize.Token
+	end       bool
+}
+
+// newTrie returns a trie of all aliases of entities. Aliases are inserted in
+// declaration order. If several aliases have the same tokens under the
+// matching mode, the first declared alias is kept.
+func newTrie(
+	entities []Entity,
+	tokens map[string][]tokenize.Token,
+	mode Match,
+) *trie {
+	root := &trie{}
+	for _, entity := range entities {
+		for _, alias := range entity.Aliases {
+			toks := tokens[alias]
+			if len(toks) == 0 {
+				continue
+			}
+
+			node := root
+			for _, token := range toks {
+				key := mode.key(&token)
+				if node.children == nil {
+					node.children = make(map[string]*trie)
+				}
+				child, ok := node.children[key]
+				if !ok {
+					child = &trie{}
+					node.children[key] = child
+				}
+				node = child
+			}
+			if node.end {
+				continue
+			}
+			node.id = entity.ID
+			node.alias = alias
+			node.tokens = toks
+			node.end = true
+		}
+	}
+
+	return root
+}
+
+// all yields the nodes and numbers of keys of all aliases at the beginning of
+// keys, longest first.
+func (t *trie) all(keys []string) iter.Seq2[*trie, int] {
+	nodes := make([]*trie, 0)
+	for node, i := t, 0; i < len(keys); i++ {
+		child, ok := node.children[keys[i]]
+		if !ok {
+			break
+		}
+		node = child
+		nodes = append(nodes, node)
+	}
+
+	return func(yield func(*trie, int) bool) {
+		for n := len(nodes); n > 0; n-- {
+			if !nodes[n-1].end {
+				continue
+			}
+			if !yield(nodes[n-1], n) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package entitydebs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ndabAP/entitydebs/testhelper"
+	"github.com/ndabAP/entitydebs/tokenize"
+)
+
+func Test_trieAll(t *testing.T) {
+	t.Parallel()
+
+	var (
+		entities = []Entity{
+			{ID: "us", Aliases: []string{"United States", "US", "us"}},
+			{ID: "army", Aliases: []string{"United States Army"}},
+		}
+		tokens = map[string][]tokenize.Token{
+			"United States": {
+				*testhelper.NewToken(t, "United", 0, nil, nil, ""),
+				*testhelper.NewToken(t, "States", 0, nil, nil, ""),
+			},
+			"US": {*testhelper.NewToken(t, "US", 0, nil, nil, "")},
+			"us": {*testhelper.NewToken(t, "us", 0, nil, nil, "")},
+			"United States Army": {
+				*testhelper.NewToken(t, "United", 0, nil, nil, ""),
+				*testhelper.NewToken(t, "States", 0, nil, nil, ""),
+				*testhelper.NewToken(t, "Army", 0, nil, nil, ""),
+			},
+		}
+	)
+
+	type result struct {
+		ID, Alias string
+		N         int
+	}
+	tests := []struct {
+		name string
+		mode Match
+		keys []string
+		want []result
+	}{
+		{
+			name: "longest alias first",
+			keys: []string{"United", "States", "Army", "."},
+			want: []result{
+				{"army", "United States Army", 3},
+				{"us", "United States", 2},
+			},
+		},
+		{
+			name: "shorter alias",
+			keys: []string{"United", "States", "Navy"},
+			want: []result{{"us", "United States", 2}},
+		},
+		{
+			name: "prefix only",
+			keys: []string{"United", "Kingdom"},
+		},
+		{
+			name: "first declared alias",
+			mode: MatchFold,
+			keys: []string{"us"},
+			want: []result{{"us", "US", 1}},
+		},
+		{
+			name: "no keys",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []result
+			for node, n := range newTrie(entities, tokens, tt.mode).all(tt.keys) {
+				got = append(got, result{node.id, node.alias, n})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("trie.all(%v) mismatch (-want +got):\n%s", tt.keys, diff)
+			}
+		})
+	}
+}
+
+func Test_sourceFramesDeterministic(t *testing.T) {
+	t.Parallel()
+
+	var (
+		entity = []string{"United States", "United States Army", "States", "Army"}
+		texts  = []string{"Pay tribute to our great United States Army."}
+		want   []byte
+	)
+	for range 20 {
+		frames, err := NewSource(entity, texts).Frames(t.Context(), mockTokenizer{}, tokenize.FeatureSyntax)
+		if err != nil {
+			t.Fatalf("source.Frames() = _, %s, want nil", err)
+		}
+
+		frame := frames.frames[0]
+		if got := frame.matches[5].alias; got != "United States Army" {
+			t.Fatalf("source.Frames().frames[0].matches[5].alias = %q, want %q", got, "United States Army")
+		}
+		got := testhelper.MarshalJSON(t, frames)
+		if want == nil {
+			want = got
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("source.Frames() = %s, want %s", got, want)
+		}
+	}
+}